@@ -0,0 +1,186 @@
+package xerrors
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/hardiksachan/x/xlog"
+	"google.golang.org/grpc/codes"
+)
+
+// Code represents a machine-readable error code.
+type Code uint8
+
+// Error codes.
+const (
+	Other Code = iota
+	Internal
+	Invalid
+	NotFound
+	Exists
+	Expired
+)
+
+// CodeInfo describes how an error code is presented.
+type CodeInfo struct {
+	// Name is the human-readable name of the code.
+	Name string
+
+	// GrpcCode is the gRPC status code errors with this code translate to.
+	// codes.OK, the zero value, is treated as codes.Unknown.
+	GrpcCode codes.Code
+
+	// HTTPStatus is the HTTP status code errors with this code translate to.
+	// Values below 100, such as the zero value, are treated as 500.
+	HTTPStatus int
+
+	// Retryable reports whether errors with this code are worth retrying.
+	Retryable bool
+
+	// LogLevel is the level errors with this code are logged at by
+	// xlog.LogError. Nil, the zero value, logs them at xlog.ErrorLevel.
+	LogLevel *xlog.Level
+}
+
+// LogAt returns a pointer to l, for setting CodeInfo.LogLevel.
+func LogAt(l xlog.Level) *xlog.Level {
+	return &l
+}
+
+// logLevel returns the level errors with the code are logged at.
+func (info CodeInfo) logLevel() xlog.Level {
+	if info.LogLevel == nil {
+		return xlog.ErrorLevel
+	}
+	return *info.LogLevel
+}
+
+var registry = struct {
+	sync.RWMutex
+	codes map[Code]CodeInfo
+	names map[string]Code
+}{
+	RWMutex: sync.RWMutex{},
+	codes:   make(map[Code]CodeInfo),
+	names:   make(map[string]Code),
+}
+
+func init() {
+	RegisterCode(Other, CodeInfo{
+		Name:       "other error",
+		GrpcCode:   codes.Unknown,
+		HTTPStatus: http.StatusInternalServerError,
+		Retryable:  true,
+		LogLevel:   LogAt(xlog.ErrorLevel),
+	})
+	RegisterCode(Internal, CodeInfo{
+		Name:       "internal error",
+		GrpcCode:   codes.Internal,
+		HTTPStatus: http.StatusInternalServerError,
+		Retryable:  true,
+		LogLevel:   LogAt(xlog.ErrorLevel),
+	})
+	RegisterCode(Invalid, CodeInfo{
+		Name:       "invalid error",
+		GrpcCode:   codes.InvalidArgument,
+		HTTPStatus: http.StatusBadRequest,
+		Retryable:  false,
+		LogLevel:   LogAt(xlog.InfoLevel),
+	})
+	RegisterCode(NotFound, CodeInfo{
+		Name:       "item not found",
+		GrpcCode:   codes.NotFound,
+		HTTPStatus: http.StatusNotFound,
+		Retryable:  false,
+		LogLevel:   LogAt(xlog.InfoLevel),
+	})
+	RegisterCode(Exists, CodeInfo{
+		Name:       "item already exists",
+		GrpcCode:   codes.AlreadyExists,
+		HTTPStatus: http.StatusConflict,
+		Retryable:  false,
+		LogLevel:   LogAt(xlog.InfoLevel),
+	})
+	RegisterCode(Expired, CodeInfo{
+		Name:       "item has expired",
+		GrpcCode:   codes.DeadlineExceeded,
+		HTTPStatus: http.StatusGone,
+		Retryable:  false,
+		LogLevel:   LogAt(xlog.InfoLevel),
+	})
+}
+
+// RegisterCode declares a new error code and returns it, so that it can be
+// used to initialise a package-level variable:
+//
+//	var Conflict = xerrors.RegisterCode(100, xerrors.CodeInfo{
+//		Name:       "conflict",
+//		GrpcCode:   codes.Aborted,
+//		HTTPStatus: http.StatusConflict,
+//		Retryable:  false,
+//		LogLevel:   xerrors.LogAt(xlog.WarnLevel),
+//	})
+//
+// RegisterCode panics if the code or its name is already registered, so
+// conflicting declarations are caught when the program starts.
+func RegisterCode(c Code, info CodeInfo) Code {
+	registry.Lock()
+	defer registry.Unlock()
+
+	if prev, ok := registry.codes[c]; ok {
+		panic(fmt.Sprintf("xerrors: code %d already registered as %q", c, prev.Name))
+	}
+	if prev, ok := registry.names[info.Name]; ok {
+		panic(fmt.Sprintf("xerrors: code name %q already registered for code %d", info.Name, prev))
+	}
+
+	registry.codes[c] = info
+	registry.names[info.Name] = c
+	return c
+}
+
+// Info returns the registered description of the code.
+func (c Code) Info() (CodeInfo, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	info, ok := registry.codes[c]
+	return info, ok
+}
+
+// String returns the string representation of the error code.
+func (c Code) String() string {
+	if info, ok := c.Info(); ok {
+		return info.Name
+	}
+	return "unknown error code"
+}
+
+// MarshalText encodes the code as its registered name, or as its number if
+// it is not registered.
+func (c Code) MarshalText() ([]byte, error) {
+	if info, ok := c.Info(); ok {
+		return []byte(info.Name), nil
+	}
+	return strconv.AppendUint(nil, uint64(c), 10), nil
+}
+
+// UnmarshalText decodes a code from its registered name or its number.
+func (c *Code) UnmarshalText(text []byte) error {
+	registry.RLock()
+	code, ok := registry.names[string(text)]
+	registry.RUnlock()
+	if ok {
+		*c = code
+		return nil
+	}
+
+	n, err := strconv.ParseUint(string(text), 10, 8)
+	if err != nil {
+		return fmt.Errorf("xerrors: unknown code %q", text)
+	}
+	*c = Code(n)
+	return nil
+}
@@ -0,0 +1,97 @@
+package xerrors_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hardiksachan/x/xerrors"
+	"github.com/hardiksachan/x/xlog"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var conflict = xerrors.RegisterCode(200, xerrors.CodeInfo{
+	Name:       "conflict",
+	GrpcCode:   codes.Aborted,
+	HTTPStatus: http.StatusConflict,
+	Retryable:  false,
+	LogLevel:   xerrors.LogAt(xlog.WarnLevel),
+})
+
+// bare is registered without gRPC and HTTP translations or a log level.
+var bare = xerrors.RegisterCode(203, xerrors.CodeInfo{Name: "bare"}) //nolint:exhaustruct
+
+func TestRegisterCode(t *testing.T) {
+	t.Run("built-in codes are registered", func(t *testing.T) {
+		info, ok := xerrors.NotFound.Info()
+		require.True(t, ok)
+		require.Equal(t, "item not found", info.Name)
+		require.Equal(t, codes.NotFound, info.GrpcCode)
+		require.Equal(t, http.StatusNotFound, info.HTTPStatus)
+	})
+
+	t.Run("registered codes behave like built-in codes", func(t *testing.T) {
+		err := xerrors.E(xerrors.Op("test"), conflict, xerrors.Message("version mismatch"))
+
+		require.Equal(t, conflict, xerrors.ErrorCode(err))
+		require.Equal(t, "conflict", conflict.String())
+		require.Contains(t, err.Error(), "<conflict>")
+		require.Equal(t, codes.Aborted, status.Code(xerrors.GrpcError(err)))
+	})
+
+	t.Run("codes without a gRPC code are not reported as OK", func(t *testing.T) {
+		err := xerrors.GrpcError(xerrors.E(xerrors.Op("test"), bare))
+
+		require.Error(t, err)
+		require.Equal(t, codes.Unknown, status.Code(err))
+	})
+
+	t.Run("unregistered codes are unknown", func(t *testing.T) {
+		_, ok := xerrors.Code(201).Info()
+		require.False(t, ok)
+		require.Equal(t, "unknown error code", xerrors.Code(201).String())
+	})
+
+	t.Run("codes round-trip through text", func(t *testing.T) {
+		for _, c := range []xerrors.Code{xerrors.NotFound, conflict, 201} {
+			text, err := c.MarshalText()
+			require.NoError(t, err)
+
+			var got xerrors.Code
+			require.NoError(t, got.UnmarshalText(text))
+			require.Equal(t, c, got)
+		}
+
+		text, _ := xerrors.Code(201).MarshalText()
+		require.Equal(t, "201", string(text))
+
+		var c xerrors.Code
+		require.Error(t, c.UnmarshalText([]byte("no such code")))
+		require.Error(t, c.UnmarshalText([]byte("256")))
+	})
+
+	t.Run("registering a code twice panics", func(t *testing.T) {
+		require.Panics(t, func() {
+			xerrors.RegisterCode(xerrors.NotFound, xerrors.CodeInfo{
+				Name:       "missing",
+				GrpcCode:   codes.NotFound,
+				HTTPStatus: http.StatusNotFound,
+				Retryable:  false,
+				LogLevel:   xerrors.LogAt(xlog.InfoLevel),
+			})
+		})
+	})
+
+	t.Run("registering a name twice panics", func(t *testing.T) {
+		require.Panics(t, func() {
+			xerrors.RegisterCode(202, xerrors.CodeInfo{
+				Name:       "conflict",
+				GrpcCode:   codes.Aborted,
+				HTTPStatus: http.StatusConflict,
+				Retryable:  false,
+				LogLevel:   xerrors.LogAt(xlog.InfoLevel),
+			})
+		})
+	})
+}
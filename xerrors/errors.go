@@ -6,10 +6,8 @@ package xerrors
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
-	"runtime"
-
-	"github.com/hardiksachan/x/xlog"
 )
 
 type (
@@ -18,40 +16,8 @@ type (
 
 	// Message represents a human-readable message.
 	Message string
-
-	// Code represents a machine-readable error code.
-	Code uint8
-)
-
-// Error codes.
-const (
-	Other Code = iota
-	Internal
-	Invalid
-	NotFound
-	Exists
-	Expired
 )
 
-// String returns the string representation of the error code.
-func (c *Code) String() string {
-	switch *c {
-	case Other:
-		return "other error"
-	case Internal:
-		return "internal error"
-	case Invalid:
-		return "invalid error"
-	case NotFound:
-		return "item not found"
-	case Exists:
-		return "item already exists"
-	case Expired:
-		return "item has expired"
-	}
-	return "unknown error code"
-}
-
 // Error represents a custom error type that supports wrapping and unwrapping.
 type Error struct {
 	// Machine-readable error code.
@@ -60,13 +26,39 @@ type Error struct {
 	// Human-readable message.
 	Message Message
 
+	// Localizable message key and the parameters of its template.
+	Key    MessageKey
+	Params MessageParams
+
 	// Logical operation and nested error.
 	Op  Op
 	Err error
+
+	// Structured metadata.
+	Fields Fields
+
+	// Retry overrides the retryability derived from Code.
+	Retry Retry
+
+	// Program counters captured at creation, if enabled.
+	stack []uintptr
+
+	// liftedCode is the code this error had before E moved it to the error
+	// wrapping it, so that errors.Is can still match it with this Op.
+	liftedCode Code
 }
 
-// Error returns the string representation of the error message.
+// Error returns the string representation of the error message, with
+// sensitive data redacted. See Redact and Unredacted.
 func (e *Error) Error() string {
+	return Redact(e.render(func(err error) string {
+		return err.Error()
+	}))
+}
+
+// render returns the string representation of the error, rendering the
+// nested error with nested.
+func (e *Error) render(nested func(error) string) string {
 	var buf bytes.Buffer
 
 	// Print the current operation in our stack, if any.
@@ -83,35 +75,70 @@ func (e *Error) Error() string {
 	}
 
 	if e.Err != nil {
-		_, _ = fmt.Fprintf(&buf, "\n\t\t%s", e.Err.Error())
+		_, _ = fmt.Fprintf(&buf, "\n\t\t%s", nested(e.Err))
 	}
 
 	return buf.String()
 }
 
 // ErrorCode returns the code of the root error, if available. Otherwise returns Internal.
+// The error chain is followed through foreign wrappers and joined errors.
 func ErrorCode(err error) Code {
 	if err == nil {
 		return Other
-	} else if e, ok := err.(*Error); ok && e.Code != Other {
-		return e.Code
-	} else if ok && e.Err != nil {
-		return ErrorCode(e.Err)
 	}
-	return Internal
+
+	code := Internal
+	walk(err, func(err error) bool {
+		switch e := err.(type) {
+		case *Error:
+			if e.Code != Other {
+				code = e.Code
+				return false
+			}
+		case Violations:
+			if len(e) > 0 {
+				code = Invalid
+				return false
+			}
+		}
+		return true
+	})
+	return code
 }
 
 // ErrorMessage returns the human-readable message of the error, if available.
-// Otherwise, returns a generic error message.
+// Otherwise, returns a generic error message. The error chain is followed
+// through foreign wrappers and joined errors.
 func ErrorMessage(err error) Message {
 	if err == nil {
 		return ""
-	} else if e, ok := err.(*Error); ok && e.Message != "" {
-		return e.Message
-	} else if ok && e.Err != nil {
-		return ErrorMessage(e.Err)
 	}
-	return "An internal error has occurred. Please contact technical support."
+
+	message, _ := errorMessage(err)
+	return message
+}
+
+// errorMessage returns the message of the error, and whether it has one
+// rather than the generic message.
+func errorMessage(err error) (Message, bool) {
+	message, found := Message("An internal error has occurred. Please contact technical support."), false
+	walk(err, func(err error) bool {
+		switch e := err.(type) {
+		case *Error:
+			if e.Message != "" {
+				message, found = e.Message, true
+				return false
+			}
+		case Violations:
+			if len(e) > 0 {
+				message, found = e.message(), true
+				return false
+			}
+		}
+		return true
+	})
+	return message, found
 }
 
 // E creates an error from a list of arguments. The arguments are processed in
@@ -121,51 +148,103 @@ func ErrorMessage(err error) Message {
 // The following types are accepted as arguments:
 // xerrors.Op
 // xerrors.Message
+// xerrors.MessageKey
+// xerrors.MessageParams
 // xerrors.Code
 // xerrors.Error
+// xerrors.Stack
+// xerrors.Fields
+// xerrors.Retry
 // error
 //
+// A stack trace is captured if enabled with SetStackCapture, unless
+// overridden by passing WithStack or NoStack.
+//
+// Other arguments are a bug at the call site. Unless strict mode is enabled
+// with SetStrict, E does not panic on them: a string becomes the Message, a
+// fmt.Stringer is stringified into the Message, and anything else is
+// recorded as an internal-only field with a logged warning, making the
+// error Internal if it has no other code.
+//
 //nolint:cyclop
 func E(args ...interface{}) error {
 	if len(args) == 0 {
-		panic("call to errors.E with no arguments")
+		badCall("call to xerrors.E with no arguments")
+		return &Error{
+			Code:    Internal,
+			Message: "",
+			Key:     "",
+			Params:  nil,
+			Op:      "",
+			Err:     errors.New("call to xerrors.E with no arguments"),
+			Fields:  nil,
+			Retry:   RetryDefault,
+			stack:   callers(1),
+
+			liftedCode: Other,
+		}
 	}
 	//nolint:exhaustruct
 	e := &Error{}
+	withStack := captureStack.Load()
+	badArgs := false
 	for _, arg := range args {
 		switch arg := arg.(type) {
 		case Op:
 			e.Op = arg
 		case Message:
 			e.Message = arg
+		case MessageKey:
+			e.Key = arg
+		case MessageParams:
+			e.Params = arg
 		case Code:
 			e.Code = arg
+		case Stack:
+			withStack = bool(arg)
+		case Fields:
+			e.Fields = e.Fields.merge(arg)
+		case Retry:
+			e.Retry = arg
 		case *Error:
 			argCopy := *arg
 			e.Err = &argCopy
 		case error:
 			e.Err = arg
+		case string:
+			badCall(fmt.Sprintf("string %q passed to xerrors.E, use xerrors.Message", arg))
+			e.Message = Message(arg)
+		case fmt.Stringer:
+			badCall(fmt.Sprintf("%T passed to xerrors.E, use xerrors.Message", arg))
+			e.Message = Message(arg.String())
 		default:
-			_, file, line, _ := runtime.Caller(1)
-			xlog.Errorf("errors.E: bad call from %s:%d: %v", file, line, args)
-			panic(fmt.Sprintf("unknown type %T, value %v in error call", arg, arg))
+			badCall(fmt.Sprintf("unknown type %T, value %v in error call", arg, arg))
+			e.Fields = e.Fields.merge(Fields{badArgumentField: InternalOnly(fmt.Sprintf("%T(%v)", arg, arg))})
+			badArgs = true
 		}
 	}
 
-	prev, ok := e.Err.(*Error)
-	if !ok {
-		return e
+	if withStack {
+		e.stack = callers(1)
 	}
 
-	// The previous error was also one of ours. Suppress duplications
-	// so the message won't contain the same kind, or file name twice.
-	if prev.Code == e.Code {
-		prev.Code = Other
+	if prev, ok := e.Err.(*Error); ok {
+		// The previous error was also one of ours. Suppress duplications
+		// so the message won't contain the same kind, or file name twice.
+		if prev.Code == e.Code {
+			prev.liftedCode, prev.Code = prev.Code, Other
+		}
+		// If this error has Kind unset or Other, pull up the inner one.
+		if e.Code == Other {
+			e.Code = prev.Code
+			if prev.Code != Other {
+				prev.liftedCode, prev.Code = prev.Code, Other
+			}
+		}
 	}
-	// If this error has Kind unset or Other, pull up the inner one.
-	if e.Code == Other {
-		e.Code = prev.Code
-		prev.Code = Other
+
+	if badArgs && e.Code == Other {
+		e.Code = Internal
 	}
 	return e
 }
@@ -0,0 +1,84 @@
+package xerrors_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/hardiksachan/x/xerrors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorCode(t *testing.T) {
+	notFound := xerrors.E(xerrors.Op("repo.Get"), xerrors.NotFound, errors.New("no rows"))
+
+	t.Run("nil error has no code", func(t *testing.T) {
+		require.Equal(t, xerrors.Other, xerrors.ErrorCode(nil))
+	})
+
+	t.Run("foreign errors are internal", func(t *testing.T) {
+		require.Equal(t, xerrors.Internal, xerrors.ErrorCode(errors.New("boom")))
+	})
+
+	t.Run("code is pulled up through nested errors", func(t *testing.T) {
+		err := xerrors.E(xerrors.Op("service.Get"), notFound)
+		require.Equal(t, xerrors.NotFound, xerrors.ErrorCode(err))
+	})
+
+	t.Run("code is found through foreign wrappers", func(t *testing.T) {
+		err := xerrors.E(xerrors.Op("service.Get"), fmt.Errorf("wrapped: %w", notFound))
+		require.Equal(t, xerrors.NotFound, xerrors.ErrorCode(err))
+	})
+
+	t.Run("code is found in joined errors", func(t *testing.T) {
+		err := errors.Join(errors.New("boom"), notFound)
+		require.Equal(t, xerrors.NotFound, xerrors.ErrorCode(err))
+	})
+}
+
+func TestErrorMessage(t *testing.T) {
+	t.Run("message is found through foreign wrappers", func(t *testing.T) {
+		inner := xerrors.E(xerrors.Op("repo.Get"), xerrors.Message("user not found"))
+		err := xerrors.E(xerrors.Op("service.Get"), fmt.Errorf("wrapped: %w", inner))
+		require.Equal(t, xerrors.Message("user not found"), xerrors.ErrorMessage(err))
+	})
+
+	t.Run("errors without a message get a generic one", func(t *testing.T) {
+		err := xerrors.E(xerrors.Op("service.Get"), errors.New("boom"))
+		require.NotEmpty(t, xerrors.ErrorMessage(err))
+		require.NotContains(t, xerrors.ErrorMessage(err), "boom")
+	})
+}
+
+func TestIsAs(t *testing.T) {
+	root := errors.New("no rows")
+	inner := xerrors.E(xerrors.Op("repo.Get"), xerrors.NotFound, root)
+	err := fmt.Errorf("service: %w", xerrors.E(xerrors.Op("service.Get"), inner))
+
+	t.Run("errors.Is finds the root cause", func(t *testing.T) {
+		require.ErrorIs(t, err, root)
+	})
+
+	t.Run("errors.Is matches by code", func(t *testing.T) {
+		require.ErrorIs(t, err, &xerrors.Error{Code: xerrors.NotFound})
+		require.NotErrorIs(t, err, &xerrors.Error{Code: xerrors.Exists})
+	})
+
+	t.Run("errors.Is matches by code and op", func(t *testing.T) {
+		require.ErrorIs(t, err, &xerrors.Error{Code: xerrors.NotFound, Op: "service.Get"})
+		require.ErrorIs(t, err, &xerrors.Error{Code: xerrors.NotFound, Op: "repo.Get"})
+		require.NotErrorIs(t, err, &xerrors.Error{Code: xerrors.NotFound, Op: "other.Op"})
+		require.NotErrorIs(t, err, &xerrors.Error{Code: xerrors.Exists, Op: "repo.Get"})
+
+		outer := xerrors.E(xerrors.Op("service.Get"), xerrors.NotFound, xerrors.E(xerrors.Op("repo.Get"), root))
+		require.ErrorIs(t, outer, &xerrors.Error{Code: xerrors.NotFound, Op: "service.Get"})
+		require.NotErrorIs(t, outer, &xerrors.Error{Code: xerrors.NotFound, Op: "repo.Get"})
+	})
+
+	t.Run("errors.As finds the outermost error", func(t *testing.T) {
+		var e *xerrors.Error
+		require.ErrorAs(t, err, &e)
+		require.Equal(t, xerrors.Op("service.Get"), e.Op)
+		require.Equal(t, xerrors.NotFound, e.Code)
+	})
+}
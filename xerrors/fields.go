@@ -0,0 +1,155 @@
+package xerrors
+
+import (
+	"fmt"
+)
+
+// Fields are structured key/value pairs attached to an error.
+type Fields map[string]interface{}
+
+// internalValue wraps a field value that must not leave the service.
+type internalValue struct {
+	value interface{}
+}
+
+// sensitiveValue wraps a field value that must not be logged or leave the
+// service.
+type sensitiveValue struct {
+	value interface{}
+}
+
+// InternalOnly marks a field value as internal. Internal fields are logged,
+// but never included in gRPC or HTTP responses.
+//
+//	xerrors.E(op, xerrors.Fields{"user_id": id, "query": xerrors.InternalOnly(q)})
+func InternalOnly(value interface{}) interface{} {
+	return internalValue{value}
+}
+
+// Sensitive marks a field value as sensitive. Sensitive fields are redacted
+// from logs and never included in gRPC or HTTP responses; only ErrorFields
+// returns them.
+func Sensitive(value interface{}) interface{} {
+	return sensitiveValue{value}
+}
+
+// fieldView decides how field values are presented to a sink.
+type fieldView uint8
+
+const (
+	// rawView shows every field as is, for internal sinks.
+	rawView fieldView = iota
+	// logView shows internal fields and redacts sensitive data, for logs.
+	logView
+	// publicView hides internal and sensitive fields and redacts sensitive
+	// data, for clients.
+	publicView
+)
+
+// present returns the value as seen by the view, and whether it is visible.
+func (view fieldView) present(value interface{}) (interface{}, bool) {
+	switch v := value.(type) {
+	case internalValue:
+		if view == publicView {
+			return nil, false
+		}
+		value = v.value
+	case sensitiveValue:
+		switch view {
+		case rawView:
+			return v.value, true
+		case logView:
+			return redacted, true
+		case publicView:
+			return nil, false
+		}
+	}
+
+	if view == rawView {
+		return value, true
+	}
+	switch v := value.(type) {
+	case string:
+		return Redact(v), true
+	case error:
+		return Redact(v.Error()), true
+	}
+	return value, true
+}
+
+// merge returns a new Fields with the entries of f and other, other taking
+// precedence.
+func (f Fields) merge(other Fields) Fields {
+	merged := make(Fields, len(f)+len(other))
+	for k, v := range f {
+		merged[k] = v
+	}
+	for k, v := range other {
+		merged[k] = v
+	}
+	return merged
+}
+
+// view returns the fields as seen by the view.
+func (f Fields) view(view fieldView) Fields {
+	if len(f) == 0 {
+		return nil
+	}
+
+	visible := make(Fields, len(f))
+	for k, v := range f {
+		if v, ok := view.present(v); ok {
+			visible[k] = v
+		}
+	}
+	return visible
+}
+
+// collectFields merges the fields of every error in the chain. Fields of
+// outer errors take precedence over those of the errors they wrap.
+func collectFields(err error, view fieldView) Fields {
+	fields := make(Fields)
+	seen := make(map[string]bool)
+	walk(err, func(err error) bool {
+		e, ok := err.(*Error)
+		if !ok {
+			return true
+		}
+		for k, v := range e.Fields {
+			if seen[k] {
+				continue
+			}
+			seen[k] = true
+			if v, ok := view.present(v); ok {
+				fields[k] = v
+			}
+		}
+		return true
+	})
+	return fields
+}
+
+// ErrorFields returns the fields of every error in the chain, including
+// internal and sensitive ones, without redaction.
+func ErrorFields(err error) Fields {
+	return collectFields(err, rawView)
+}
+
+// PublicFields returns the fields of every error in the chain that are safe
+// to send to clients, with sensitive data redacted.
+func PublicFields(err error) Fields {
+	return collectFields(err, publicView)
+}
+
+// stringFields formats every field value as a string.
+func stringFields(fields Fields) map[string]string {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	data := make(map[string]string, len(fields))
+	for k, v := range fields {
+		data[k] = fmt.Sprint(v)
+	}
+	return data
+}
@@ -0,0 +1,53 @@
+package xerrors_test
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hardiksachan/x/xerrors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFields(t *testing.T) {
+	inner := xerrors.E(
+		xerrors.Op("repo.GetUser"),
+		xerrors.NotFound,
+		xerrors.Fields{"user_id": 42, "table": "users", "query": xerrors.InternalOnly("SELECT 1")},
+	)
+	err := xerrors.E(xerrors.Op("service.GetUser"), fmt.Errorf("wrapped: %w", inner), xerrors.Fields{"table": "accounts"})
+
+	t.Run("fields are merged up the chain, outer errors winning", func(t *testing.T) {
+		require.Equal(t, xerrors.Fields{
+			"user_id": 42,
+			"table":   "accounts",
+			"query":   "SELECT 1",
+		}, xerrors.ErrorFields(err))
+	})
+
+	t.Run("internal fields are not public", func(t *testing.T) {
+		require.Equal(t, xerrors.Fields{
+			"user_id": 42,
+			"table":   "accounts",
+		}, xerrors.PublicFields(err))
+	})
+
+	t.Run("public fields survive the gRPC round trip", func(t *testing.T) {
+		got := xerrors.FromGrpcError(xerrors.GrpcError(err))
+		require.Equal(t, xerrors.Fields{
+			"user_id": "42",
+			"table":   "accounts",
+		}, xerrors.ErrorFields(got))
+	})
+
+	t.Run("public fields are written to problem responses", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		xerrors.WriteHTTPError(rec, err)
+
+		problem := decodeProblem(t, rec)
+		require.Equal(t, xerrors.Fields{
+			"user_id": float64(42),
+			"table":   "accounts",
+		}, problem.Fields)
+	})
+}
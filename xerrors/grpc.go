@@ -1,38 +1,218 @@
 package xerrors
 
 import (
-	"github.com/hardiksachan/x/xlog"
+	"strconv"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
-// GrpcCode returns the gRPC code for the given error.
+const (
+	// grpcErrorDomain identifies status details written by this package.
+	grpcErrorDomain = "github.com/hardiksachan/x/xerrors"
+
+	grpcCodeKey = "code"
+
+	// grpcCodeOpKey is the op of the error that set the code, sent with the
+	// op chain.
+	grpcCodeOpKey = "code_op"
+
+	// grpcFieldPrefix prefixes the keys of public fields in the details.
+	grpcFieldPrefix = "field."
+)
+
+// grpcCode returns the gRPC code for the given error code. Codes that are
+// unregistered or registered without a gRPC code map to codes.Unknown, so
+// they are never reported as OK.
 func (c Code) grpcCode() codes.Code {
-	switch c {
-	case Other:
-		return codes.Unknown
-	case Internal:
-		return codes.Internal
-	case Invalid:
-		return codes.InvalidArgument
-	case NotFound:
-		return codes.NotFound
-	case Exists:
-		return codes.AlreadyExists
-	case Expired:
-		return codes.DeadlineExceeded
+	if info, ok := c.Info(); ok && info.GrpcCode != codes.OK {
+		return info.GrpcCode
 	}
 	return codes.Unknown
 }
 
-// GrpcError returns a gRPC error for the given error.
-func GrpcError(err error) error {
+// codeFromGrpc returns the registered error code for the given gRPC code.
+// If several codes share the gRPC code, the lowest one is chosen, so the
+// built-in codes take precedence.
+func codeFromGrpc(c codes.Code) Code {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	code, found := Other, false
+	for k, info := range registry.codes {
+		if info.GrpcCode == c && (!found || k < code) {
+			code, found = k, true
+		}
+	}
+	return code
+}
+
+// GrpcOption configures how errors are translated to gRPC statuses.
+type GrpcOption func(*grpcOptions)
+
+type grpcOptions struct {
+	includeOps bool
+	locales    []string
+}
+
+// WithGrpcOps includes the op chain of the error in the status details.
+// Use it only between trusted services, as it exposes internals.
+func WithGrpcOps() GrpcOption {
+	return func(o *grpcOptions) {
+		o.includeOps = true
+	}
+}
+
+// WithGrpcLocale localizes the status message in the first of the locales
+// that has a translation.
+func WithGrpcLocale(locales ...string) GrpcOption {
+	return func(o *grpcOptions) {
+		o.locales = locales
+	}
+}
+
+// GrpcError returns a gRPC error for the given error. The error code, and
+// optionally the op chain, are attached as status details so that
+// FromGrpcError can restore them on the client side.
+func GrpcError(err error, opts ...GrpcOption) error {
+	o := grpcOptions{
+		includeOps: false,
+		locales:    nil,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	code := ErrorCode(err)
 
-	xlog.ErrorString(err.Error())
+	logError(err)
 
-	grpcCode := code.grpcCode()
-	message := ErrorMessage(err)
+	st := status.New(code.grpcCode(), string(localize(err, o.locales)))
 
-	return status.Errorf(grpcCode, string(message))
+	metadata := map[string]string{
+		grpcCodeKey: strconv.Itoa(int(code)),
+	}
+	for k, v := range stringFields(PublicFields(err)) {
+		metadata[grpcFieldPrefix+k] = v
+	}
+	if op := codeOp(err); o.includeOps && op != "" {
+		metadata[grpcCodeOpKey] = string(op)
+	}
+
+	//nolint:exhaustruct
+	info := &errdetails.ErrorInfo{
+		Reason:   code.String(),
+		Domain:   grpcErrorDomain,
+		Metadata: metadata,
+	}
+	withDetails, detailsErr := st.WithDetails(info)
+	if detailsErr != nil {
+		return st.Err()
+	}
+
+	if v := violations(err); len(v) > 0 {
+		badRequest := &errdetails.BadRequest{
+			FieldViolations: make([]*errdetails.BadRequest_FieldViolation, 0, len(v)),
+		}
+		for _, violation := range v {
+			//nolint:exhaustruct
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       violation.Field,
+				Description: string(violation.Message),
+			})
+		}
+		if withViolations, violationsErr := withDetails.WithDetails(badRequest); violationsErr == nil {
+			withDetails = withViolations
+		}
+	}
+
+	if o.includeOps {
+		entries := make([]string, 0)
+		for _, op := range Ops(err) {
+			entries = append(entries, string(op))
+		}
+		//nolint:exhaustruct
+		withOps, opsErr := withDetails.WithDetails(&errdetails.DebugInfo{StackEntries: entries})
+		if opsErr == nil {
+			withDetails = withOps
+		}
+	}
+
+	return withDetails.Err()
+}
+
+// FromGrpcError converts an error returned by a gRPC call back into an
+// *Error. The code, message, public fields and op chain are restored from the details
+// written by GrpcError; for other statuses the code is derived from the gRPC
+// code. The original status error is kept as the root cause. Errors that do
+// not carry a gRPC status are returned unchanged.
+func FromGrpcError(err error) error {
+	st, ok := status.FromError(err)
+	if !ok || st.Code() == codes.OK {
+		return err
+	}
+
+	code := codeFromGrpc(st.Code())
+	var fields Fields
+	var remoteOps []Op
+	var remoteCodeOp Op
+	for _, detail := range st.Details() {
+		switch detail := detail.(type) {
+		case *errdetails.ErrorInfo:
+			if detail.GetDomain() != grpcErrorDomain {
+				continue
+			}
+			if c, parseErr := strconv.ParseUint(detail.GetMetadata()[grpcCodeKey], 10, 8); parseErr == nil {
+				code = Code(c)
+			}
+			fields = fieldsFromMetadata(detail.GetMetadata())
+			remoteCodeOp = Op(detail.GetMetadata()[grpcCodeOpKey])
+		case *errdetails.DebugInfo:
+			for _, entry := range detail.GetStackEntries() {
+				remoteOps = append(remoteOps, Op(entry))
+			}
+		}
+	}
+
+	var op Op
+	var cause error = err
+	if len(remoteOps) > 0 {
+		op = remoteOps[0]
+		lifted := remoteCodeOp == op
+		for i := len(remoteOps) - 1; i > 0; i-- {
+			//nolint:exhaustruct
+			e := &Error{Op: remoteOps[i], Err: cause}
+			if !lifted && remoteOps[i] == remoteCodeOp {
+				e.liftedCode, lifted = code, true
+			}
+			cause = e
+		}
+	}
+
+	//nolint:exhaustruct
+	return &Error{
+		Code:    code,
+		Message: Message(st.Message()),
+		Op:      op,
+		Err:     cause,
+		Fields:  fields,
+	}
+}
+
+// fieldsFromMetadata extracts the fields written by GrpcError.
+func fieldsFromMetadata(metadata map[string]string) Fields {
+	var fields Fields
+	for k, v := range metadata {
+		key, ok := strings.CutPrefix(k, grpcFieldPrefix)
+		if !ok {
+			continue
+		}
+		if fields == nil {
+			fields = make(Fields)
+		}
+		fields[key] = v
+	}
+	return fields
 }
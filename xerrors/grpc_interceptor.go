@@ -0,0 +1,152 @@
+package xerrors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const acceptLanguageKey = "accept-language"
+
+// UnaryServerInterceptor returns a gRPC interceptor that translates errors
+// returned by unary handlers with GrpcError, and recovers from panics by
+// returning an Internal error. Messages are localized using the
+// accept-language metadata of the call.
+func UnaryServerInterceptor(opts ...GrpcOption) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (resp interface{}, err error) {
+		callOpts := withIncomingLocale(ctx, opts)
+		defer recoverGrpc(info.FullMethod, &err, callOpts)
+
+		resp, err = handler(ctx, req)
+		return resp, serverError(err, callOpts)
+	}
+}
+
+// StreamServerInterceptor returns a gRPC interceptor that translates errors
+// returned by streaming handlers with GrpcError, and recovers from panics by
+// returning an Internal error. Messages are localized using the
+// accept-language metadata of the call.
+func StreamServerInterceptor(opts ...GrpcOption) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) (err error) {
+		callOpts := opts
+		if ss != nil {
+			callOpts = withIncomingLocale(ss.Context(), opts)
+		}
+		defer recoverGrpc(info.FullMethod, &err, callOpts)
+
+		return serverError(handler(srv, ss), callOpts)
+	}
+}
+
+// UnaryClientInterceptor returns a gRPC interceptor that converts statuses
+// returned by unary calls back into *Error with FromGrpcError.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		return FromGrpcError(invoker(ctx, method, req, reply, cc, opts...))
+	}
+}
+
+// StreamClientInterceptor returns a gRPC interceptor that converts statuses
+// returned by streaming calls back into *Error with FromGrpcError.
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(
+		ctx context.Context,
+		desc *grpc.StreamDesc,
+		cc *grpc.ClientConn,
+		method string,
+		streamer grpc.Streamer,
+		opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		s, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, FromGrpcError(err)
+		}
+		return &clientStream{s}, nil
+	}
+}
+
+// clientStream converts the errors of the wrapped stream with FromGrpcError.
+type clientStream struct {
+	grpc.ClientStream
+}
+
+func (s *clientStream) SendMsg(m interface{}) error {
+	return FromGrpcError(s.ClientStream.SendMsg(m))
+}
+
+func (s *clientStream) RecvMsg(m interface{}) error {
+	return FromGrpcError(s.ClientStream.RecvMsg(m))
+}
+
+func (s *clientStream) CloseSend() error {
+	return FromGrpcError(s.ClientStream.CloseSend())
+}
+
+// serverError translates err with GrpcError. Errors that already are gRPC
+// statuses and carry no *Error are returned unchanged.
+func serverError(err error, opts []GrpcOption) error {
+	if err == nil {
+		return nil
+	}
+
+	var e *Error
+	if !errors.As(err, &e) {
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+	}
+
+	return GrpcError(err, opts...)
+}
+
+// recoverGrpc recovers from a panic in a gRPC handler, replacing the
+// handler's error with an Internal one.
+func recoverGrpc(method string, err *error, opts []GrpcOption) {
+	op := Op("xerrors.recoverGrpc")
+
+	rec := recover()
+	if rec == nil {
+		return
+	}
+
+	*err = GrpcError(E(op, Internal, fmt.Errorf("panic handling %s: %v\n%s", method, rec, debug.Stack())), opts...)
+}
+
+// withIncomingLocale adds the locales requested in the accept-language
+// metadata of ctx to opts.
+func withIncomingLocale(ctx context.Context, opts []GrpcOption) []GrpcOption {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return opts
+	}
+
+	values := md.Get(acceptLanguageKey)
+	if len(values) == 0 {
+		return opts
+	}
+
+	return append(opts[:len(opts):len(opts)], WithGrpcLocale(ParseAcceptLanguage(strings.Join(values, ","))...))
+}
@@ -0,0 +1,87 @@
+package xerrors_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hardiksachan/x/xerrors"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	interceptor := xerrors.UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{Server: nil, FullMethod: "/users.Users/Get"}
+
+	call := func(handler grpc.UnaryHandler) error {
+		_, err := interceptor(context.Background(), nil, info, handler)
+		return err
+	}
+
+	t.Run("domain errors are translated", func(t *testing.T) {
+		err := call(func(context.Context, interface{}) (interface{}, error) {
+			return nil, xerrors.E(xerrors.Op("test"), xerrors.NotFound, xerrors.Message("user not found"))
+		})
+
+		st, ok := status.FromError(err)
+		require.True(t, ok)
+		require.Equal(t, codes.NotFound, st.Code())
+		require.Equal(t, "user not found", st.Message())
+	})
+
+	t.Run("statuses are passed through", func(t *testing.T) {
+		want := status.Error(codes.Unauthenticated, "no token")
+		err := call(func(context.Context, interface{}) (interface{}, error) {
+			return nil, want
+		})
+
+		require.Equal(t, want, err)
+	})
+
+	t.Run("panics are recovered as internal errors", func(t *testing.T) {
+		err := call(func(context.Context, interface{}) (interface{}, error) {
+			panic("boom")
+		})
+
+		st, ok := status.FromError(err)
+		require.True(t, ok)
+		require.Equal(t, codes.Internal, st.Code())
+		require.NotContains(t, st.Message(), "boom")
+	})
+
+	t.Run("successful calls are untouched", func(t *testing.T) {
+		resp, err := interceptor(context.Background(), nil, info, func(context.Context, interface{}) (interface{}, error) {
+			return "ok", nil
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, "ok", resp)
+	})
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	interceptor := xerrors.StreamServerInterceptor()
+	info := &grpc.StreamServerInfo{FullMethod: "/users.Users/List", IsClientStream: false, IsServerStream: true}
+
+	err := interceptor(nil, nil, info, func(interface{}, grpc.ServerStream) error {
+		panic("boom")
+	})
+
+	require.Equal(t, codes.Internal, status.Code(err))
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	interceptor := xerrors.UnaryClientInterceptor()
+
+	err := interceptor(context.Background(), "/users.Users/Get", nil, nil, nil,
+		func(context.Context, string, interface{}, interface{}, *grpc.ClientConn, ...grpc.CallOption) error {
+			return xerrors.GrpcError(xerrors.E(xerrors.Op("test"), xerrors.Exists))
+		},
+	)
+
+	var e *xerrors.Error
+	require.ErrorAs(t, err, &e)
+	require.Equal(t, xerrors.Exists, xerrors.ErrorCode(err))
+}
@@ -0,0 +1,66 @@
+package xerrors_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/hardiksachan/x/xerrors"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGrpcError(t *testing.T) {
+	inner := xerrors.E(xerrors.Op("repo.GetUser"), xerrors.NotFound, xerrors.Message("user not found"), errors.New("no rows"))
+	err := xerrors.E(xerrors.Op("service.GetUser"), inner)
+
+	t.Run("code and message are translated", func(t *testing.T) {
+		st, ok := status.FromError(xerrors.GrpcError(err))
+		require.True(t, ok)
+		require.Equal(t, codes.NotFound, st.Code())
+		require.Equal(t, "user not found", st.Message())
+	})
+
+	t.Run("code, message and ops survive the round trip", func(t *testing.T) {
+		got := xerrors.FromGrpcError(xerrors.GrpcError(err, xerrors.WithGrpcOps()))
+
+		require.Equal(t, xerrors.NotFound, xerrors.ErrorCode(got))
+		require.Equal(t, xerrors.Message("user not found"), xerrors.ErrorMessage(got))
+		require.ErrorIs(t, got, &xerrors.Error{Code: xerrors.NotFound, Op: "service.GetUser"})
+		require.ErrorIs(t, got, &xerrors.Error{Code: xerrors.NotFound, Op: "repo.GetUser"})
+	})
+
+	t.Run("ops are not sent unless requested", func(t *testing.T) {
+		got := xerrors.FromGrpcError(xerrors.GrpcError(err))
+
+		var e *xerrors.Error
+		require.ErrorAs(t, got, &e)
+		require.Empty(t, e.Op)
+		require.NotContains(t, got.Error(), "repo.GetUser")
+	})
+
+	t.Run("registered codes survive the round trip", func(t *testing.T) {
+		got := xerrors.FromGrpcError(xerrors.GrpcError(xerrors.E(xerrors.Op("test"), conflict)))
+		require.Equal(t, conflict, xerrors.ErrorCode(got))
+	})
+}
+
+func TestFromGrpcError(t *testing.T) {
+	t.Run("plain statuses are mapped by gRPC code", func(t *testing.T) {
+		got := xerrors.FromGrpcError(status.Error(codes.InvalidArgument, "bad email"))
+
+		require.Equal(t, xerrors.Invalid, xerrors.ErrorCode(got))
+		require.Equal(t, xerrors.Message("bad email"), xerrors.ErrorMessage(got))
+		require.Equal(t, codes.InvalidArgument, status.Code(got))
+	})
+
+	t.Run("non-status errors are returned unchanged", func(t *testing.T) {
+		err := fmt.Errorf("boom")
+		require.Equal(t, err, xerrors.FromGrpcError(err))
+	})
+
+	t.Run("nil stays nil", func(t *testing.T) {
+		require.NoError(t, xerrors.FromGrpcError(nil))
+	})
+}
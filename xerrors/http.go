@@ -0,0 +1,117 @@
+package xerrors
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/hardiksachan/x/xlog"
+)
+
+const problemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details object.
+type Problem struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+
+	// Fields holds the public fields of the error.
+	Fields Fields `json:"fields,omitempty"`
+
+	// Errors holds the field-level validation failures, if any.
+	Errors Violations `json:"errors,omitempty"`
+}
+
+// minHTTPStatus is the lowest valid HTTP status code.
+const minHTTPStatus = 100
+
+// httpStatus returns the HTTP status code for the given error code. Codes
+// that are unregistered or registered without a valid status map to 500.
+func (c Code) httpStatus() int {
+	if info, ok := c.Info(); ok && info.HTTPStatus >= minHTTPStatus {
+		return info.HTTPStatus
+	}
+	return http.StatusInternalServerError
+}
+
+// HTTPOption configures how errors are translated to problem details.
+type HTTPOption func(*httpOptions)
+
+type httpOptions struct {
+	locales []string
+}
+
+// WithHTTPLocale localizes the problem detail in the first of the locales
+// that has a translation.
+func WithHTTPLocale(locales ...string) HTTPOption {
+	return func(o *httpOptions) {
+		o.locales = locales
+	}
+}
+
+// WithHTTPRequest localizes the problem detail using the Accept-Language
+// header of the request.
+func WithHTTPRequest(r *http.Request) HTTPOption {
+	return WithHTTPLocale(ParseAcceptLanguage(r.Header.Get("Accept-Language"))...)
+}
+
+// HTTPError returns the problem details for the given error.
+func HTTPError(err error, opts ...HTTPOption) *Problem {
+	o := httpOptions{
+		locales: nil,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	status := ErrorCode(err).httpStatus()
+
+	return &Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: string(localize(err, o.locales)),
+		Fields: PublicFields(err),
+		Errors: violations(err),
+	}
+}
+
+// WriteHTTPError logs the error and writes it to w as an
+// application/problem+json response.
+func WriteHTTPError(w http.ResponseWriter, err error, opts ...HTTPOption) {
+	logError(err)
+
+	problem := HTTPError(err, opts...)
+
+	w.Header().Set("Content-Type", problemContentType)
+	w.WriteHeader(problem.Status)
+	if err := json.NewEncoder(w).Encode(problem); err != nil {
+		xlog.Current().Warn(xlog.Messagef("xerrors: writing problem response").With(xlog.Err(err)))
+	}
+}
+
+// HTTPRecoverer is net/http middleware that recovers from panics in next
+// and renders them as Internal problems.
+func HTTPRecoverer(next http.Handler) http.Handler {
+	op := Op("xerrors.HTTPRecoverer")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			// http.ErrAbortHandler is used to abort a response on purpose.
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+
+			err := E(op, Internal, fmt.Errorf("panic serving %s %s: %v", r.Method, r.URL.Path, rec))
+			WriteHTTPError(w, err, WithHTTPRequest(r))
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,89 @@
+package xerrors_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hardiksachan/x/xerrors"
+	"github.com/hardiksachan/x/xlog"
+	"github.com/hardiksachan/x/xlog/xlogtest"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeProblem(t *testing.T, rec *httptest.ResponseRecorder) xerrors.Problem {
+	t.Helper()
+
+	require.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
+
+	var problem xerrors.Problem
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&problem))
+	return problem
+}
+
+func TestWriteHTTPError(t *testing.T) {
+	t.Run("error code is mapped to HTTP status", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		err := xerrors.E(xerrors.Op("test"), xerrors.NotFound, xerrors.Message("user not found"))
+
+		xerrors.WriteHTTPError(rec, err)
+
+		require.Equal(t, http.StatusNotFound, rec.Code)
+		problem := decodeProblem(t, rec)
+		require.Equal(t, http.StatusNotFound, problem.Status)
+		require.Equal(t, "Not Found", problem.Title)
+		require.Equal(t, "user not found", problem.Detail)
+	})
+
+	t.Run("foreign errors are internal and do not leak details", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		xerrors.WriteHTTPError(rec, errors.New("connection refused"))
+
+		require.Equal(t, http.StatusInternalServerError, rec.Code)
+		problem := decodeProblem(t, rec)
+		require.NotContains(t, problem.Detail, "connection refused")
+	})
+
+	t.Run("codes without a valid status are internal", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		xerrors.WriteHTTPError(rec, xerrors.E(xerrors.Op("test"), bare))
+
+		require.Equal(t, http.StatusInternalServerError, rec.Code)
+		require.Equal(t, http.StatusInternalServerError, decodeProblem(t, rec).Status)
+	})
+
+	t.Run("write failures are logged", func(t *testing.T) {
+		o := xlogtest.Observe(t)
+
+		xerrors.WriteHTTPError(failingWriter{ResponseWriter: httptest.NewRecorder()}, errors.New("connection refused"))
+
+		xlogtest.AssertLogged(t, o, xlog.WarnLevel, "writing problem response")
+	})
+}
+
+// failingWriter is a ResponseWriter whose body writes fail.
+type failingWriter struct {
+	http.ResponseWriter
+}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("broken pipe")
+}
+
+func TestHTTPRecoverer(t *testing.T) {
+	h := xerrors.HTTPRecoverer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("boom")
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+	problem := decodeProblem(t, rec)
+	require.Equal(t, http.StatusInternalServerError, problem.Status)
+	require.NotContains(t, problem.Detail, "boom")
+}
@@ -0,0 +1,72 @@
+package xerrors
+
+import (
+	"encoding/json"
+)
+
+// Ops returns the ops in the error chain, from the outermost error to the
+// root cause.
+func Ops(err error) []Op {
+	var ops []Op
+	walk(err, func(err error) bool {
+		if e, ok := err.(*Error); ok && e.Op != "" {
+			ops = append(ops, e.Op)
+		}
+		return true
+	})
+	return ops
+}
+
+// jsonError is a node of the JSON representation of an error tree.
+type jsonError struct {
+	Code       Code         `json:"code,omitempty"`
+	Message    Message      `json:"message,omitempty"`
+	Op         Op           `json:"op,omitempty"`
+	Ops        []Op         `json:"ops,omitempty"`
+	Fields     Fields       `json:"fields,omitempty"`
+	Error      string       `json:"error,omitempty"`
+	Violations Violations   `json:"violations,omitempty"`
+	Cause      *jsonError   `json:"cause,omitempty"`
+	Causes     []*jsonError `json:"causes,omitempty"`
+}
+
+// MarshalJSON encodes the error as a tree with its code, message, op trail,
+// fields and causes. Sensitive data is redacted.
+func (e *Error) MarshalJSON() ([]byte, error) {
+	node := toJSON(e)
+	node.Ops = Ops(e)
+	return json.Marshal(node)
+}
+
+// toJSON converts err and the errors it wraps into JSON nodes.
+func toJSON(err error) *jsonError {
+	if err == nil {
+		return nil
+	}
+
+	//nolint:exhaustruct
+	node := &jsonError{}
+	switch x := err.(type) {
+	case *Error:
+		node.Code = x.Code
+		node.Message = x.Message
+		node.Op = x.Op
+		node.Fields = x.Fields.view(logView)
+		node.Cause = toJSON(x.Err)
+		return node
+	case Violations:
+		node.Violations = x
+		return node
+	}
+
+	node.Error = Redact(err.Error())
+	switch x := err.(type) {
+	case interface{ Unwrap() error }:
+		node.Cause = toJSON(x.Unwrap())
+	case interface{ Unwrap() []error }:
+		for _, err := range x.Unwrap() {
+			node.Causes = append(node.Causes, toJSON(err))
+		}
+	}
+	return node
+}
@@ -0,0 +1,45 @@
+package xerrors_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/hardiksachan/x/xerrors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOps(t *testing.T) {
+	inner := xerrors.E(xerrors.Op("repo.GetUser"), errors.New("no rows"))
+	middle := fmt.Errorf("wrapped: %w", xerrors.E(xerrors.Op("service.GetUser"), inner))
+	err := xerrors.E(xerrors.Op("handler.GetUser"), middle)
+
+	require.Equal(t, []xerrors.Op{"handler.GetUser", "service.GetUser", "repo.GetUser"}, xerrors.Ops(err))
+	require.Empty(t, xerrors.Ops(errors.New("boom")))
+}
+
+func TestMarshalJSON(t *testing.T) {
+	inner := xerrors.E(
+		xerrors.Op("repo.GetUser"),
+		xerrors.NotFound,
+		xerrors.Message("user not found"),
+		xerrors.Fields{"user_id": 42},
+		errors.New("no rows"),
+	)
+	err := xerrors.E(xerrors.Op("service.GetUser"), inner)
+
+	got, marshalErr := json.Marshal(err)
+	require.NoError(t, marshalErr)
+	require.JSONEq(t, `{
+		"code": "item not found",
+		"op": "service.GetUser",
+		"ops": ["service.GetUser", "repo.GetUser"],
+		"cause": {
+			"message": "user not found",
+			"op": "repo.GetUser",
+			"fields": {"user_id": 42},
+			"cause": {"error": "no rows"}
+		}
+	}`, string(got))
+}
@@ -0,0 +1,165 @@
+package xerrors
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// InternalMessageKey is the key of the generic message used for errors
+// that have no message of their own.
+const InternalMessageKey MessageKey = "xerrors.internal"
+
+type (
+	// MessageKey identifies a localizable message in the Catalog.
+	MessageKey string
+
+	// MessageParams are the values substituted into a localized message.
+	// A parameter named "id" replaces "{id}" in the message template.
+	MessageParams map[string]interface{}
+)
+
+// Catalog looks up message templates by locale and key.
+type Catalog interface {
+	Lookup(locale string, key MessageKey) (string, bool)
+}
+
+// MapCatalog is a Catalog backed by a map from locale to message key to
+// template.
+type MapCatalog map[string]map[MessageKey]string
+
+// Lookup implements Catalog.
+func (c MapCatalog) Lookup(locale string, key MessageKey) (string, bool) {
+	template, ok := c[locale][key]
+	return template, ok
+}
+
+var catalog = struct {
+	sync.RWMutex
+	c Catalog
+}{
+	RWMutex: sync.RWMutex{},
+	c:       MapCatalog{},
+}
+
+// SetCatalog sets the catalog used to localize messages.
+func SetCatalog(c Catalog) {
+	catalog.Lock()
+	defer catalog.Unlock()
+
+	catalog.c = c
+}
+
+// lookup finds the template for key in the first locale that has it. Each
+// locale is tried as given, then by its base language, e.g. "pt-BR" and "pt".
+func lookup(locales []string, key MessageKey) (string, bool) {
+	catalog.RLock()
+	defer catalog.RUnlock()
+
+	for _, locale := range locales {
+		if template, ok := catalog.c.Lookup(locale, key); ok {
+			return template, true
+		}
+		if base, _, found := strings.Cut(locale, "-"); found {
+			if template, ok := catalog.c.Lookup(base, key); ok {
+				return template, true
+			}
+		}
+	}
+	return "", false
+}
+
+// render substitutes params into the template.
+func render(template string, params MessageParams) Message {
+	if len(params) == 0 {
+		return Message(template)
+	}
+
+	replacements := make([]string, 0, len(params)*2) //nolint:gomnd
+	for k, v := range params {
+		replacements = append(replacements, "{"+k+"}", fmt.Sprint(v))
+	}
+	return Message(strings.NewReplacer(replacements...).Replace(template))
+}
+
+// LocalizedMessage returns the human-readable message of the error in the
+// given locale. The message key of the outermost error that has a key or
+// message is looked up in the catalog; if it has no translation, the result
+// is the same as ErrorMessage.
+func LocalizedMessage(err error, locale string) Message {
+	return localize(err, []string{locale})
+}
+
+// localize returns the message of the error in the first of the locales
+// that has a translation.
+func localize(err error, locales []string) Message {
+	if err == nil {
+		return ""
+	}
+
+	var found *Error
+	walk(err, func(err error) bool {
+		if e, ok := err.(*Error); ok && (e.Key != "" || e.Message != "") {
+			found = e
+			return false
+		}
+		return true
+	})
+
+	if found != nil && found.Key != "" {
+		if template, ok := lookup(locales, found.Key); ok {
+			return render(template, found.Params)
+		}
+	}
+
+	message, ok := errorMessage(err)
+	if ok {
+		return message
+	}
+
+	if template, ok := lookup(locales, InternalMessageKey); ok {
+		return Message(template)
+	}
+	return message
+}
+
+// ParseAcceptLanguage returns the locales of an Accept-Language header or
+// metadata value, most preferred first.
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		locale string
+		q      float64
+	}
+
+	var tags []weighted
+	for _, part := range strings.Split(header, ",") {
+		locale, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if locale == "" || locale == "*" {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > 0 {
+			tags = append(tags, weighted{locale: locale, q: q})
+		}
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].q > tags[j].q
+	})
+
+	locales := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		locales = append(locales, tag.locale)
+	}
+	return locales
+}
@@ -0,0 +1,93 @@
+package xerrors_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hardiksachan/x/xerrors"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func setCatalog(t *testing.T) {
+	t.Helper()
+
+	xerrors.SetCatalog(xerrors.MapCatalog{
+		"es": {
+			"user.not_found":           "Usuario {id} no encontrado",
+			xerrors.InternalMessageKey: "Se ha producido un error interno",
+		},
+		"pt-BR": {
+			"user.not_found": "Usuário {id} não encontrado",
+		},
+	})
+	t.Cleanup(func() { xerrors.SetCatalog(xerrors.MapCatalog{}) })
+}
+
+func userNotFound() error {
+	return xerrors.E(
+		xerrors.Op("service.GetUser"),
+		xerrors.NotFound,
+		xerrors.Message("User 42 not found"),
+		xerrors.MessageKey("user.not_found"),
+		xerrors.MessageParams{"id": 42},
+	)
+}
+
+func TestLocalizedMessage(t *testing.T) {
+	setCatalog(t)
+
+	t.Run("messages are translated with their parameters", func(t *testing.T) {
+		require.Equal(t, xerrors.Message("Usuario 42 no encontrado"), xerrors.LocalizedMessage(userNotFound(), "es"))
+		require.Equal(t, xerrors.Message("Usuário 42 não encontrado"), xerrors.LocalizedMessage(userNotFound(), "pt-BR"))
+	})
+
+	t.Run("regional locales fall back to their base language", func(t *testing.T) {
+		require.Equal(t, xerrors.Message("Usuario 42 no encontrado"), xerrors.LocalizedMessage(userNotFound(), "es-MX"))
+	})
+
+	t.Run("missing translations fall back to the message", func(t *testing.T) {
+		require.Equal(t, xerrors.Message("User 42 not found"), xerrors.LocalizedMessage(userNotFound(), "fr"))
+	})
+
+	t.Run("the generic message is translated", func(t *testing.T) {
+		err := xerrors.E(xerrors.Op("test"), xerrors.Internal)
+		require.Equal(t, xerrors.Message("Se ha producido un error interno"), xerrors.LocalizedMessage(err, "es"))
+		require.Equal(t, xerrors.ErrorMessage(err), xerrors.LocalizedMessage(err, "fr"))
+	})
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	require.Equal(t, []string{"fr-CH", "fr", "en", "de"}, xerrors.ParseAcceptLanguage("fr-CH, fr;q=0.9, en;q=0.8, de;q=0.7, *;q=0.5"))
+	require.Equal(t, []string{"en"}, xerrors.ParseAcceptLanguage("de;q=0, en"))
+	require.Empty(t, xerrors.ParseAcceptLanguage(""))
+}
+
+func TestLocalizedTranslators(t *testing.T) {
+	setCatalog(t)
+
+	t.Run("HTTP problems use the Accept-Language header", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+		r.Header.Set("Accept-Language", "fr;q=0.9, es")
+
+		rec := httptest.NewRecorder()
+		xerrors.WriteHTTPError(rec, userNotFound(), xerrors.WithHTTPRequest(r))
+
+		require.Equal(t, "Usuario 42 no encontrado", decodeProblem(t, rec).Detail)
+	})
+
+	t.Run("gRPC statuses use the accept-language metadata", func(t *testing.T) {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("accept-language", "pt-BR"))
+		info := &grpc.UnaryServerInfo{Server: nil, FullMethod: "/users.Users/Get"}
+
+		_, err := xerrors.UnaryServerInterceptor()(ctx, nil, info, func(context.Context, interface{}) (interface{}, error) {
+			return nil, userNotFound()
+		})
+
+		require.Equal(t, "Usuário 42 não encontrado", status.Convert(err).Message())
+	})
+}
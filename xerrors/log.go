@@ -0,0 +1,108 @@
+package xerrors
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/hardiksachan/x/xlog"
+)
+
+// Keys of the fields describing an error in logs.
+const (
+	logCodeKey       = "code"
+	logOpsKey        = "ops"
+	logCausesKey     = "causes"
+	logViolationsKey = "violations"
+	logStackKey      = "stack"
+
+	// logFieldPrefix prefixes the keys of the error fields, so that they do
+	// not clash with the keys above.
+	logFieldPrefix = "field."
+)
+
+// LogMessage implements xlog.Loggable, so that xlog.LogError logs the error
+// as structured fields instead of a flattened string. The title is the
+// user message, or the code name if there is none, and the fields hold the
+// code, the op trail, the root causes, the validation failures, the stack
+// if one was captured and the error fields, sorted by key, prefixed with
+// "field." and with sensitive data redacted. The level is the LogLevel of
+// the code, or xlog.ErrorLevel if it is unset or the code is unregistered.
+func (e *Error) LogMessage() (xlog.Level, xlog.Message) {
+	code := ErrorCode(e)
+
+	title := code.String()
+	if message, ok := errorMessage(e); ok {
+		title = Redact(string(message))
+	}
+
+	fields := []xlog.Field{xlog.String(logCodeKey, code.String())}
+	if ops := Ops(e); len(ops) > 0 {
+		trail := make([]string, len(ops))
+		for i, op := range ops {
+			trail[i] = string(op)
+		}
+		fields = append(fields, xlog.Any(logOpsKey, trail))
+	}
+	if causes := rootCauses(e); len(causes) > 0 {
+		fields = append(fields, xlog.Any(logCausesKey, causes))
+	}
+	if v := violations(e); len(v) > 0 {
+		fields = append(fields, xlog.Any(logViolationsKey, v))
+	}
+	if stack := StackTrace(e); len(stack) > 0 {
+		var b strings.Builder
+		writeStack(&b, stack)
+		fields = append(fields, xlog.String(logStackKey, strings.TrimPrefix(b.String(), "\n")))
+	}
+	errFields := collectFields(e, logView)
+	keys := make([]string, 0, len(errFields))
+	for k := range errFields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fields = append(fields, xlog.Any(logFieldPrefix+k, errFields[k]))
+	}
+
+	level := xlog.ErrorLevel
+	if info, ok := code.Info(); ok {
+		level = info.logLevel()
+	}
+
+	return level, xlog.Message{
+		Title:   title,
+		Details: "",
+		Data:    nil,
+		Fields:  fields,
+	}
+}
+
+// logError logs err to the default logger. Errors that are not an *Error
+// are wrapped first, so that their message is redacted as a root cause
+// instead of being logged as is.
+func logError(err error) {
+	if err == nil {
+		return
+	}
+	if _, ok := err.(*Error); !ok { //nolint:errorlint // wrapped errors are wrapped again
+		err = E(err)
+	}
+	xlog.LogError(xlog.Current(), err)
+}
+
+// rootCauses returns the redacted messages of the errors in the chain that
+// are neither *Error nor Violations and wrap nothing.
+func rootCauses(err error) []string {
+	var causes []string
+	walk(err, func(err error) bool {
+		switch err.(type) {
+		case *Error, Violations:
+			return true
+		case interface{ Unwrap() error }, interface{ Unwrap() []error }:
+			return true
+		}
+		causes = append(causes, Redact(err.Error()))
+		return true
+	})
+	return causes
+}
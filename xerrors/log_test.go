@@ -0,0 +1,129 @@
+package xerrors_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hardiksachan/x/xerrors"
+	"github.com/hardiksachan/x/xlog"
+	"github.com/hardiksachan/x/xlog/xlogtest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogMessage(t *testing.T) {
+	t.Run("errors are logged as structured fields", func(t *testing.T) {
+		root := errors.New("dial tcp: connection refused")
+		inner := xerrors.E(xerrors.Op("repo.Get"), xerrors.Internal, root, xerrors.Fields{
+			"user_id":  "u1",
+			"password": xerrors.Sensitive("hunter2"),
+		})
+		err := xerrors.E(xerrors.Op("service.Get"), xerrors.Message("could not load the user"), inner)
+
+		o := xlog.NewObserver()
+		xlog.LogError(o, fmt.Errorf("handler: %w", err))
+
+		entries := o.Entries()
+		require.Len(t, entries, 1)
+		e := entries[0]
+
+		require.Equal(t, xlog.ErrorLevel, e.Level)
+		require.Equal(t, "could not load the user", e.Title)
+		require.Len(t, entries.WithField("code", "internal error"), 1)
+		require.Len(t, entries.WithField("ops", []string{"service.Get", "repo.Get"}), 1)
+		require.Len(t, entries.WithField("causes", []string{"dial tcp: connection refused"}), 1)
+		require.Len(t, entries.WithField("field.user_id", "u1"), 1)
+		require.NotContains(t, e.String(), "hunter2")
+	})
+
+	t.Run("level follows the code", func(t *testing.T) {
+		o := xlog.NewObserver()
+
+		xlog.LogError(o, xerrors.E(xerrors.Op("repo.Get"), xerrors.NotFound))
+		xlog.LogError(o, xerrors.E(xerrors.Op("repo.Save"), conflict))
+		xlog.LogError(o, xerrors.E(xerrors.Op("repo.Save"), xerrors.Code(250)))
+		xlog.LogError(o, xerrors.E(xerrors.Op("repo.Save"), bare))
+
+		entries := o.Entries()
+		require.Equal(t, []xlog.Level{xlog.InfoLevel, xlog.WarnLevel, xlog.ErrorLevel, xlog.ErrorLevel},
+			[]xlog.Level{entries[0].Level, entries[1].Level, entries[2].Level, entries[3].Level})
+		require.Equal(t, "item not found", entries[0].Title)
+	})
+
+	t.Run("error fields are prefixed and sorted", func(t *testing.T) {
+		err := xerrors.E(xerrors.Op("repo.Get"), xerrors.NotFound, xerrors.Fields{"code": "A1", "b": 2, "a": 1})
+
+		_, msg := xlog.ErrorMessage(err)
+
+		var keys []string
+		for _, f := range msg.Fields {
+			keys = append(keys, f.Key)
+		}
+		require.Equal(t, []string{"code", "ops", "field.a", "field.b", "field.code"}, keys)
+		require.Equal(t, "item not found", msg.Fields[0].Value())
+	})
+
+	t.Run("violations and stacks are logged", func(t *testing.T) {
+		var v xerrors.Violations
+		v.Add("email", xerrors.Invalid, "must be a valid email")
+		err := xerrors.E(xerrors.Op("service.Create"), xerrors.WithStack, v.Err())
+
+		level, msg := xlog.ErrorMessage(err)
+		require.Equal(t, xlog.InfoLevel, level)
+
+		entry := xlog.Entry{Level: level, Title: msg.Title, Details: "", Data: nil, Fields: msg.Fields}
+		violations, ok := entry.Value("violations")
+		require.True(t, ok)
+		require.Equal(t, v, violations)
+
+		stack, ok := entry.Value("stack")
+		require.True(t, ok)
+		require.Contains(t, stack, "TestLogMessage")
+	})
+
+	t.Run("foreign errors are redacted when translated", func(t *testing.T) {
+		o := xlogtest.Observe(t)
+		drv := errors.New(`pq: duplicate key value violates unique constraint "users_email_key": 'jane.doe@example.com'`)
+
+		_ = xerrors.GrpcError(drv)
+		xerrors.WriteHTTPError(httptest.NewRecorder(), fmt.Errorf("wrap: %w", drv))
+
+		entries := o.Entries()
+		require.Len(t, entries, 2)
+		for _, e := range entries {
+			require.Equal(t, xlog.ErrorLevel, e.Level)
+			require.NotContains(t, e.String(), "jane.doe@example.com")
+			require.Contains(t, e.String(), "users_email_key")
+		}
+	})
+
+	t.Run("HTTP errors are logged at the level of their code", func(t *testing.T) {
+		o := xlogtest.Observe(t)
+
+		xerrors.WriteHTTPError(httptest.NewRecorder(), xerrors.E(xerrors.Op("repo.Get"), xerrors.NotFound))
+
+		xlogtest.AssertLogged(t, o, xlog.InfoLevel, "item not found")
+		require.Len(t, o.Entries().ByLevel(xlog.ErrorLevel), 0)
+	})
+
+	t.Run("the caller of translated errors is the handler", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, err := xlog.New(xlog.Config{Format: xlog.FormatJSON, Output: &buf, Async: nil})
+		require.NoError(t, err)
+		prev := xlog.SwapDefault(logger)
+		t.Cleanup(func() { xlog.SetDefault(prev) })
+
+		_ = xerrors.GrpcError(xerrors.E(xerrors.Op("repo.Get"), xerrors.Internal))
+		xerrors.WriteHTTPError(httptest.NewRecorder(), xerrors.E(xerrors.Op("repo.Get"), xerrors.Internal))
+
+		decoder := json.NewDecoder(&buf)
+		for i := 0; i < 2; i++ {
+			var entry map[string]interface{}
+			require.NoError(t, decoder.Decode(&entry))
+			require.Contains(t, entry["caller"], "xerrors/log_test.go")
+		}
+	})
+}
@@ -0,0 +1,80 @@
+package xerrors
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// redacted replaces sensitive data.
+const redacted = "[REDACTED]"
+
+// Common patterns of sensitive data. If a pattern has a group named
+// "secret", only that group is replaced.
+var (
+	EmailPattern          = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	BearerTokenPattern    = regexp.MustCompile(`(?i)\bbearer\s+(?P<secret>[A-Za-z0-9\-._~+/]+=*)`)
+	URLCredentialsPattern = regexp.MustCompile(`://(?P<secret>[^/\s:@]+:[^/\s@]+)@`)
+)
+
+var redaction = struct {
+	sync.RWMutex
+	patterns []*regexp.Regexp
+}{
+	RWMutex:  sync.RWMutex{},
+	patterns: []*regexp.Regexp{EmailPattern, BearerTokenPattern, URLCredentialsPattern},
+}
+
+// SetRedactionPatterns replaces the patterns scrubbed from rendered errors,
+// logs and responses. By default, emails, bearer tokens and credentials in
+// URLs are scrubbed. Calling it with no patterns disables redaction.
+func SetRedactionPatterns(patterns ...*regexp.Regexp) {
+	redaction.Lock()
+	defer redaction.Unlock()
+
+	redaction.patterns = patterns
+}
+
+// Redact replaces every match of the redaction patterns in s.
+func Redact(s string) string {
+	redaction.RLock()
+	defer redaction.RUnlock()
+
+	for _, pattern := range redaction.patterns {
+		group := pattern.SubexpIndex("secret")
+		if group < 0 {
+			s = pattern.ReplaceAllString(s, redacted)
+			continue
+		}
+		s = replaceGroup(s, pattern, group)
+	}
+	return s
+}
+
+// replaceGroup replaces the given group of every match of pattern in s.
+func replaceGroup(s string, pattern *regexp.Regexp, group int) string {
+	var b strings.Builder
+	last := 0
+	for _, match := range pattern.FindAllStringSubmatchIndex(s, -1) {
+		start, end := match[2*group], match[2*group+1]
+		if start < 0 {
+			continue
+		}
+		b.WriteString(s[last:start])
+		b.WriteString(redacted)
+		last = end
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// Unredacted returns the string representation of the error without
+// redaction. It is meant for internal sinks only, such as an audit log that
+// is allowed to hold personal data. Errors wrapped by foreign errors are
+// rendered by their wrapper, and so remain redacted.
+func Unredacted(err error) string {
+	if e, ok := err.(*Error); ok {
+		return e.render(Unredacted)
+	}
+	return err.Error()
+}
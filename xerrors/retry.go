@@ -0,0 +1,47 @@
+package xerrors
+
+// Retry overrides whether an error is worth retrying.
+type Retry uint8
+
+// Retry classifications.
+const (
+	// RetryDefault derives retryability from the wrapped errors and the code.
+	RetryDefault Retry = iota
+	// Temporary marks an error as worth retrying.
+	Temporary
+	// Permanent marks an error as not worth retrying.
+	Permanent
+)
+
+// IsRetryable reports whether the operation that failed with err is worth
+// retrying. The outermost error marked Temporary or Permanent decides;
+// failing that, a wrapped error whose Temporary() or Timeout() method
+// reports true makes it retryable; otherwise the registered code decides.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	retryable, decided := false, false
+	walk(err, func(err error) bool {
+		if e, ok := err.(*Error); ok && e.Retry != RetryDefault {
+			retryable, decided = e.Retry == Temporary, true
+			return false
+		}
+		if t, ok := err.(interface{ Temporary() bool }); ok && t.Temporary() {
+			retryable, decided = true, true
+			return false
+		}
+		if t, ok := err.(interface{ Timeout() bool }); ok && t.Timeout() {
+			retryable, decided = true, true
+			return false
+		}
+		return true
+	})
+	if decided {
+		return retryable
+	}
+
+	info, ok := ErrorCode(err).Info()
+	return !ok || info.Retryable
+}
@@ -0,0 +1,87 @@
+package xerrors
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"sync/atomic"
+)
+
+const maxStackDepth = 32
+
+// Stack controls whether E captures a stack trace for a single call,
+// overriding the global setting configured with SetStackCapture.
+type Stack bool
+
+// Per-call stack capture settings.
+const (
+	WithStack Stack = true
+	NoStack   Stack = false
+)
+
+var captureStack atomic.Bool
+
+// SetStackCapture enables or disables stack capture for every error created
+// with E. It is disabled by default.
+func SetStackCapture(enabled bool) {
+	captureStack.Store(enabled)
+}
+
+// callers returns the program counters of the caller of E. skip is the
+// number of frames above callers to skip.
+func callers(skip int) []uintptr {
+	var pcs [maxStackDepth]uintptr
+	n := runtime.Callers(skip+2, pcs[:])
+	return pcs[:n]
+}
+
+// Stack returns the program counters captured when the error was created, if any.
+func (e *Error) Stack() []uintptr {
+	return e.stack
+}
+
+// StackTrace returns the stack of the innermost error in the chain that has
+// one, which is the closest to where the failure originated.
+func StackTrace(err error) []uintptr {
+	var stack []uintptr
+	walk(err, func(err error) bool {
+		if e, ok := err.(*Error); ok && len(e.stack) > 0 {
+			stack = e.stack
+		}
+		return true
+	})
+	return stack
+}
+
+// Format implements fmt.Formatter. The %+v verb prints the error followed by
+// the stack trace of its origin, if one was captured.
+func (e *Error) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		_, _ = io.WriteString(s, e.Error())
+		if s.Flag('+') {
+			writeStack(s, StackTrace(e))
+		}
+	case 's':
+		_, _ = io.WriteString(s, e.Error())
+	case 'q':
+		_, _ = fmt.Fprintf(s, "%q", e.Error())
+	default:
+		_, _ = fmt.Fprintf(s, "%%!%c(*xerrors.Error=%s)", verb, e.Error())
+	}
+}
+
+func writeStack(w io.Writer, stack []uintptr) {
+	if len(stack) == 0 {
+		return
+	}
+
+	frames := runtime.CallersFrames(stack)
+	for {
+		frame, more := frames.Next()
+		_, _ = fmt.Fprintf(w, "\n%s\n\t%s:%d", frame.Function, frame.File, frame.Line)
+		if !more {
+			return
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package xerrors_test
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/hardiksachan/x/xerrors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStackCapture(t *testing.T) {
+	t.Run("stack is not captured by default", func(t *testing.T) {
+		err := xerrors.E(xerrors.Op("test"), errors.New("boom"))
+
+		var e *xerrors.Error
+		require.ErrorAs(t, err, &e)
+		require.Empty(t, e.Stack())
+	})
+
+	t.Run("WithStack captures the stack of the caller", func(t *testing.T) {
+		err := xerrors.E(xerrors.Op("test"), xerrors.WithStack, errors.New("boom"))
+
+		var e *xerrors.Error
+		require.ErrorAs(t, err, &e)
+		require.NotEmpty(t, e.Stack())
+
+		frame, _ := runtime.CallersFrames(e.Stack()).Next()
+		require.Contains(t, frame.Function, "TestStackCapture")
+	})
+
+	t.Run("global setting can be overridden per call", func(t *testing.T) {
+		xerrors.SetStackCapture(true)
+		t.Cleanup(func() { xerrors.SetStackCapture(false) })
+
+		err := xerrors.E(xerrors.Op("test"), xerrors.NoStack, errors.New("boom"))
+
+		var e *xerrors.Error
+		require.ErrorAs(t, err, &e)
+		require.Empty(t, e.Stack())
+	})
+
+	t.Run("%+v prints the op chain and the origin of the error", func(t *testing.T) {
+		inner := xerrors.E(xerrors.Op("inner"), xerrors.WithStack, errors.New("boom"))
+		outer := xerrors.E(xerrors.Op("outer"), inner)
+
+		require.Equal(t, outer.Error(), fmt.Sprintf("%v", outer))
+
+		verbose := fmt.Sprintf("%+v", outer)
+		require.Contains(t, verbose, outer.Error())
+		require.Contains(t, verbose, "TestStackCapture")
+		require.Contains(t, verbose, "stack_test.go")
+		require.Equal(t, xerrors.StackTrace(inner), xerrors.StackTrace(outer))
+	})
+}
@@ -0,0 +1,32 @@
+package xerrors
+
+import (
+	"runtime"
+	"sync/atomic"
+
+	"github.com/hardiksachan/x/xlog"
+)
+
+// badArgumentField records arguments E did not understand.
+const badArgumentField = "xerrors.bad_argument"
+
+var strict atomic.Bool
+
+// SetStrict makes E panic on arguments it does not accept, instead of
+// recovering from them. Strict mode is meant for tests.
+func SetStrict(enabled bool) {
+	strict.Store(enabled)
+}
+
+// badCall reports a bad call to E. It panics in strict mode and logs a
+// warning otherwise.
+func badCall(problem string) {
+	// Skip badCall and E to report the caller of E.
+	_, file, line, _ := runtime.Caller(2)
+	if strict.Load() {
+		xlog.Errorf("xerrors.E: bad call from %s:%d: %s", file, line, problem)
+		panic(problem)
+	}
+
+	xlog.Warnf("xerrors.E: bad call from %s:%d: %s", file, line, problem)
+}
@@ -0,0 +1,53 @@
+package xerrors_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hardiksachan/x/xerrors"
+	"github.com/hardiksachan/x/xtest"
+	"github.com/stretchr/testify/require"
+)
+
+type stringer struct{}
+
+func (stringer) String() string { return "from stringer" }
+
+func TestLenientArguments(t *testing.T) {
+	t.Run("no arguments is an internal error", func(t *testing.T) {
+		err := xerrors.E()
+		require.Error(t, err)
+		require.Equal(t, xerrors.Internal, xerrors.ErrorCode(err))
+	})
+
+	t.Run("strings become the message", func(t *testing.T) {
+		err := xerrors.E(xerrors.Op("test"), xerrors.NotFound, "user not found")
+		require.Equal(t, xerrors.Message("user not found"), xerrors.ErrorMessage(err))
+		require.Equal(t, xerrors.NotFound, xerrors.ErrorCode(err))
+	})
+
+	t.Run("stringers become the message", func(t *testing.T) {
+		err := xerrors.E(xerrors.Op("test"), stringer{})
+		require.Equal(t, xerrors.Message("from stringer"), xerrors.ErrorMessage(err))
+	})
+
+	t.Run("unknown arguments are recorded as internal errors", func(t *testing.T) {
+		err := xerrors.E(xerrors.Op("test"), 42, errors.New("boom"))
+		require.Equal(t, xerrors.Internal, xerrors.ErrorCode(err))
+		require.Equal(t, "int(42)", xerrors.ErrorFields(err)["xerrors.bad_argument"])
+		require.Empty(t, xerrors.PublicFields(err))
+	})
+}
+
+func TestStrictArguments(t *testing.T) {
+	xtest.StrictErrors(t)
+
+	require.Panics(t, func() { _ = xerrors.E() })
+	require.Panics(t, func() { _ = xerrors.E(xerrors.Op("test"), "user not found") })
+	require.Panics(t, func() { _ = xerrors.E(xerrors.Op("test"), 42) })
+	require.NotPanics(t, func() { _ = xerrors.E(xerrors.Op("test"), xerrors.Message("user not found")) })
+}
+
+func TestErrorCallSites(t *testing.T) {
+	xtest.CheckErrorCalls(t, "..")
+}
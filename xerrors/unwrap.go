@@ -0,0 +1,71 @@
+package xerrors
+
+// Unwrap returns the nested error, if any.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is an *Error with the same Code and, if target
+// has an Op, the same Op. This lets errors.Is match errors by code, or by
+// the op that set the code:
+//
+//	errors.Is(err, &xerrors.Error{Code: xerrors.NotFound})
+//	errors.Is(err, &xerrors.Error{Code: xerrors.NotFound, Op: "repo.Get"})
+//
+// A code moved to the outer error when wrapping still matches with the Op
+// of the error that set it.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+
+	if t.Op == "" {
+		return t.Code == e.Code
+	}
+	if t.Op != e.Op {
+		return false
+	}
+	return t.Code == e.Code || (e.Code == Other && t.Code == e.liftedCode)
+}
+
+// codeOp returns the op of the innermost error that set the code of err,
+// if it has one.
+func codeOp(err error) Op {
+	code := ErrorCode(err)
+
+	var op Op
+	walk(err, func(err error) bool {
+		if e, ok := err.(*Error); ok && e.Op != "" && (e.Code == code || e.liftedCode == code) {
+			op = e.Op
+		}
+		return true
+	})
+	return op
+}
+
+// walk visits err and every error it wraps in depth-first order, following
+// both Unwrap() error and Unwrap() []error, until visit returns false.
+// It reports whether the walk ran to completion.
+func walk(err error, visit func(error) bool) bool {
+	if err == nil {
+		return true
+	}
+
+	if !visit(err) {
+		return false
+	}
+
+	switch x := err.(type) {
+	case interface{ Unwrap() error }:
+		return walk(x.Unwrap(), visit)
+	case interface{ Unwrap() []error }:
+		for _, err := range x.Unwrap() {
+			if !walk(err, visit) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
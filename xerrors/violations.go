@@ -0,0 +1,74 @@
+package xerrors
+
+import (
+	"errors"
+	"strings"
+)
+
+// Violation describes why a single field failed validation.
+type Violation struct {
+	// Field is the path to the field, e.g. "address.zip_code".
+	Field string `json:"field"`
+
+	// Code is the reason for the violation. Defaults to Invalid.
+	Code Code `json:"code"`
+
+	// Message is a human-readable description of the violation.
+	Message Message `json:"message"`
+}
+
+// Violations collects field-level validation failures so they can be
+// reported at once. It reports Invalid through ErrorCode.
+//
+//	var v xerrors.Violations
+//	if req.Email == "" {
+//		v.Add("email", xerrors.Invalid, "must not be empty")
+//	}
+//	if err := v.Err(); err != nil {
+//		return xerrors.E(op, err)
+//	}
+type Violations []Violation
+
+// Add records a violation. A code of Other is recorded as Invalid.
+func (v *Violations) Add(field string, code Code, message Message) {
+	if code == Other {
+		code = Invalid
+	}
+
+	*v = append(*v, Violation{
+		Field:   field,
+		Code:    code,
+		Message: message,
+	})
+}
+
+// Err returns the violations as an error, or nil if there are none.
+func (v Violations) Err() error {
+	if len(v) == 0 {
+		return nil
+	}
+	return v
+}
+
+// Error returns every violation, separated by semicolons.
+func (v Violations) Error() string {
+	return string(v.message())
+}
+
+// message renders every violation as "field: message".
+func (v Violations) message() Message {
+	parts := make([]string, 0, len(v))
+	for _, violation := range v {
+		parts = append(parts, violation.Field+": "+string(violation.Message))
+	}
+	return Message(strings.Join(parts, "; "))
+}
+
+// violations returns the violations in the error chain, if any.
+func violations(err error) Violations {
+	var v Violations
+	if errors.As(err, &v) {
+		return v
+	}
+	return nil
+}
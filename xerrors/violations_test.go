@@ -0,0 +1,66 @@
+package xerrors_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hardiksachan/x/xerrors"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newViolations() xerrors.Violations {
+	var v xerrors.Violations
+	v.Add("email", xerrors.Invalid, "must not be empty")
+	v.Add("address.zip_code", xerrors.Other, "must be 5 digits")
+	return v
+}
+
+func TestViolations(t *testing.T) {
+	err := xerrors.E(xerrors.Op("service.CreateUser"), newViolations().Err())
+
+	t.Run("no violations is no error", func(t *testing.T) {
+		var v xerrors.Violations
+		require.NoError(t, v.Err())
+	})
+
+	t.Run("violations are invalid", func(t *testing.T) {
+		require.Equal(t, xerrors.Invalid, xerrors.ErrorCode(err))
+	})
+
+	t.Run("every violation is rendered in the message", func(t *testing.T) {
+		require.Equal(t,
+			xerrors.Message("email: must not be empty; address.zip_code: must be 5 digits"),
+			xerrors.ErrorMessage(err),
+		)
+	})
+
+	t.Run("violations are sent as gRPC bad request details", func(t *testing.T) {
+		st, ok := status.FromError(xerrors.GrpcError(err))
+		require.True(t, ok)
+		require.Equal(t, codes.InvalidArgument, st.Code())
+
+		var badRequest *errdetails.BadRequest
+		for _, detail := range st.Details() {
+			if d, ok := detail.(*errdetails.BadRequest); ok {
+				badRequest = d
+			}
+		}
+		require.NotNil(t, badRequest)
+		require.Len(t, badRequest.GetFieldViolations(), 2)
+		require.Equal(t, "email", badRequest.GetFieldViolations()[0].GetField())
+		require.Equal(t, "must not be empty", badRequest.GetFieldViolations()[0].GetDescription())
+	})
+
+	t.Run("violations are written as problem errors", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		xerrors.WriteHTTPError(rec, err)
+
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+		problem := decodeProblem(t, rec)
+		require.Equal(t, newViolations(), problem.Errors)
+	})
+}
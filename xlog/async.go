@@ -0,0 +1,307 @@
+package xlog
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// OverflowPolicy decides what an Async logger does when its buffer is full.
+type OverflowPolicy int
+
+// Overflow policies.
+const (
+	// Block waits until there is room in the buffer.
+	Block OverflowPolicy = iota
+	// DropOldest discards the oldest buffered message.
+	DropOldest
+	// DropNewest discards the message being logged.
+	DropNewest
+)
+
+const defaultAsyncSize = 1024
+
+// AsyncConfig configures an Async logger.
+type AsyncConfig struct {
+	// Size is the number of messages buffered. Defaults to 1024.
+	Size int
+
+	// Policy decides what happens when the buffer is full. Defaults to Block.
+	Policy OverflowPolicy
+}
+
+// Syncer is implemented by loggers that buffer output, and is used by the
+// package-level Flush.
+type Syncer interface {
+	Sync() error
+}
+
+// Closer is implemented by loggers that must be stopped, and is used by the
+// package-level Close.
+type Closer interface {
+	Close() error
+}
+
+// Dropper is implemented by loggers that drop messages, such as Async when
+// its buffer is full and Sampler, and is used by the package-level Dropped.
+type Dropper interface {
+	Dropped() uint64
+}
+
+// wrapper is implemented by the loggers of this package that forward to
+// another logger.
+type wrapper interface {
+	unwrap() Log
+}
+
+// enabler is implemented by loggers that filter messages by level.
+type enabler interface {
+	enabled(l Level) bool
+}
+
+type asyncEntry struct {
+	level Level
+	msg   Message
+}
+
+// Async is a Log that buffers messages and writes them to the next logger
+// in a background goroutine, so logging does not wait for I/O. Call Flush
+// or Close before exiting so buffered messages are not lost.
+type Async struct {
+	next   Log
+	policy OverflowPolicy
+
+	mu       sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	idle     *sync.Cond
+	queue    []asyncEntry
+	head     int
+	n        int
+	writing  bool
+	closed   bool
+
+	done    chan struct{}
+	dropped atomic.Uint64
+}
+
+// NewAsync returns an Async logger writing to next, and starts its
+// background goroutine.
+func NewAsync(next Log, cfg AsyncConfig) *Async {
+	size := cfg.Size
+	if size <= 0 {
+		size = defaultAsyncSize
+	}
+
+	a := &Async{
+		next:     next,
+		policy:   cfg.Policy,
+		mu:       sync.Mutex{},
+		notEmpty: nil,
+		notFull:  nil,
+		idle:     nil,
+		queue:    make([]asyncEntry, size),
+		head:     0,
+		n:        0,
+		writing:  false,
+		closed:   false,
+		done:     make(chan struct{}),
+		dropped:  atomic.Uint64{},
+	}
+	a.notEmpty = sync.NewCond(&a.mu)
+	a.notFull = sync.NewCond(&a.mu)
+	a.idle = sync.NewCond(&a.mu)
+
+	go a.run()
+	return a
+}
+
+// Dropped returns the number of messages dropped because the buffer was full.
+func (a *Async) Dropped() uint64 {
+	return a.dropped.Load()
+}
+
+// Info buffers an info message.
+func (a *Async) Info(msg Message) {
+	a.enqueue(InfoLevel, msg)
+}
+
+// Warn buffers a warning message.
+func (a *Async) Warn(msg Message) {
+	a.enqueue(WarnLevel, msg)
+}
+
+// Error buffers an error message.
+func (a *Async) Error(msg Message) {
+	a.enqueue(ErrorLevel, msg)
+}
+
+// Debug buffers a debug message.
+func (a *Async) Debug(msg Message) {
+	a.enqueue(DebugLevel, msg)
+}
+
+// Flush waits until every buffered message is written, then syncs the next
+// logger if it is a Syncer.
+func (a *Async) Flush() error {
+	a.mu.Lock()
+	for a.n > 0 || a.writing {
+		a.idle.Wait()
+	}
+	a.mu.Unlock()
+
+	return syncLog(a.next)
+}
+
+// Sync is an alias of Flush.
+func (a *Async) Sync() error {
+	return a.Flush()
+}
+
+// Close writes the buffered messages and stops the background goroutine.
+// Messages logged afterwards are written synchronously.
+func (a *Async) Close() error {
+	a.mu.Lock()
+	a.closed = true
+	a.notEmpty.Broadcast()
+	a.notFull.Broadcast()
+	a.mu.Unlock()
+
+	<-a.done
+	return syncLog(a.next)
+}
+
+func (a *Async) unwrap() Log {
+	return a.next
+}
+
+func (a *Async) enabled(l Level) bool {
+	return logEnabled(a.next, l)
+}
+
+func (a *Async) enqueue(l Level, msg Message) {
+	if !logEnabled(a.next, l) {
+		return
+	}
+	if _, ok := messageCaller(msg); !ok {
+		msg = msg.With(callerAt(callerPC()))
+	}
+
+	a.mu.Lock()
+	for !a.closed && a.n == len(a.queue) {
+		switch a.policy {
+		case DropNewest:
+			a.mu.Unlock()
+			a.dropped.Add(1)
+			return
+		case DropOldest:
+			a.head = (a.head + 1) % len(a.queue)
+			a.n--
+			a.dropped.Add(1)
+		case Block:
+			a.notFull.Wait()
+		}
+	}
+	if a.closed {
+		a.mu.Unlock()
+		a.write(asyncEntry{level: l, msg: msg})
+		return
+	}
+
+	a.queue[(a.head+a.n)%len(a.queue)] = asyncEntry{level: l, msg: msg}
+	a.n++
+	a.notEmpty.Signal()
+	a.mu.Unlock()
+}
+
+func (a *Async) run() {
+	defer close(a.done)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for {
+		for a.n == 0 && !a.closed {
+			a.notEmpty.Wait()
+		}
+		if a.n == 0 {
+			return
+		}
+
+		e := a.queue[a.head]
+		a.queue[a.head] = asyncEntry{level: 0, msg: Message{Title: "", Details: "", Data: nil, Fields: nil}}
+		a.head = (a.head + 1) % len(a.queue)
+		a.n--
+		a.writing = true
+		a.notFull.Signal()
+		a.mu.Unlock()
+
+		a.write(e)
+
+		a.mu.Lock()
+		a.writing = false
+		if a.n == 0 {
+			a.idle.Broadcast()
+		}
+	}
+}
+
+func (a *Async) write(e asyncEntry) {
+	LogAt(a.next, e.level, e.msg)
+}
+
+// syncLog syncs l if it is a Syncer.
+func syncLog(l Log) error {
+	if s, ok := l.(Syncer); ok {
+		return s.Sync()
+	}
+	return nil
+}
+
+// closeLog closes l if it is a Closer, and syncs it otherwise.
+func closeLog(l Log) error {
+	if c, ok := l.(Closer); ok {
+		return c.Close()
+	}
+	return syncLog(l)
+}
+
+// droppedBy returns the number of messages dropped by l and the loggers it
+// forwards to.
+func droppedBy(l Log) uint64 {
+	var n uint64
+	if d, ok := l.(Dropper); ok {
+		n = d.Dropped()
+	}
+	if w, ok := l.(wrapper); ok {
+		n += droppedBy(w.unwrap())
+	}
+	return n
+}
+
+// logEnabled reports whether l logs messages at the given level, so that
+// they are not buffered only to be dropped.
+func logEnabled(l Log, level Level) bool {
+	if e, ok := l.(enabler); ok {
+		return e.enabled(level)
+	}
+	return true
+}
+
+// Flush writes the messages buffered by the default logger, e.g. before the
+// process exits.
+func Flush() error {
+	return syncLog(currentLogger())
+}
+
+// Close flushes and stops the default logger if it is a Closer, and flushes
+// it otherwise.
+func Close() error {
+	return closeLog(currentLogger())
+}
+
+// Dropped returns the number of messages dropped by the default logger and
+// the loggers it forwards to, e.g. because an Async buffer was full, for
+// use as a metric.
+func Dropped() uint64 {
+	return droppedBy(currentLogger())
+}
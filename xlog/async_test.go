@@ -0,0 +1,201 @@
+package xlog_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hardiksachan/x/xlog"
+	"github.com/stretchr/testify/require"
+)
+
+// gatedLog records messages once the gate is opened.
+type gatedLog struct {
+	*xlog.Observer
+	gate chan struct{}
+}
+
+func newGatedLog() *gatedLog {
+	return &gatedLog{Observer: xlog.NewObserver(), gate: make(chan struct{})}
+}
+
+func (g *gatedLog) Info(msg xlog.Message) {
+	<-g.gate
+	g.Observer.Info(msg)
+}
+
+// fill logs a first message, which the background goroutine picks up and
+// blocks on, then n more to fill the buffer.
+func fill(t *testing.T, a *xlog.Async, n int) {
+	t.Helper()
+
+	a.Info(xlog.Messagef("0"))
+	time.Sleep(10 * time.Millisecond)
+	for i := 1; i <= n; i++ {
+		a.Info(xlog.Messagef("%d", i))
+	}
+}
+
+func TestAsync(t *testing.T) {
+	t.Run("messages are written in order by Flush", func(t *testing.T) {
+		o := xlog.NewObserver()
+		a := xlog.NewAsync(o, xlog.AsyncConfig{Size: 4, Policy: xlog.Block})
+		t.Cleanup(func() { require.NoError(t, a.Close()) })
+
+		for i := 0; i < 10; i++ {
+			a.Info(xlog.Messagef("%d", i))
+		}
+		require.NoError(t, a.Flush())
+
+		require.Equal(t, []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9"}, o.Entries().Titles())
+		require.Zero(t, a.Dropped())
+	})
+
+	t.Run("DropNewest discards messages logged while full", func(t *testing.T) {
+		g := newGatedLog()
+		a := xlog.NewAsync(g, xlog.AsyncConfig{Size: 2, Policy: xlog.DropNewest})
+
+		fill(t, a, 4)
+		close(g.gate)
+		require.NoError(t, a.Close())
+
+		require.Equal(t, []string{"0", "1", "2"}, g.Entries().Titles())
+		require.Equal(t, uint64(2), a.Dropped())
+	})
+
+	t.Run("DropOldest discards buffered messages", func(t *testing.T) {
+		g := newGatedLog()
+		a := xlog.NewAsync(g, xlog.AsyncConfig{Size: 2, Policy: xlog.DropOldest})
+
+		fill(t, a, 4)
+		close(g.gate)
+		require.NoError(t, a.Close())
+
+		require.Equal(t, []string{"0", "3", "4"}, g.Entries().Titles())
+		require.Equal(t, uint64(2), a.Dropped())
+	})
+
+	t.Run("Block waits for room in the buffer", func(t *testing.T) {
+		g := newGatedLog()
+		a := xlog.NewAsync(g, xlog.AsyncConfig{Size: 2, Policy: xlog.Block})
+
+		fill(t, a, 2)
+		logged := make(chan struct{})
+		go func() {
+			a.Info(xlog.Messagef("3"))
+			close(logged)
+		}()
+
+		select {
+		case <-logged:
+			t.Fatal("logging did not block while the buffer was full")
+		case <-time.After(20 * time.Millisecond):
+		}
+
+		close(g.gate)
+		<-logged
+		require.NoError(t, a.Close())
+
+		require.Equal(t, []string{"0", "1", "2", "3"}, g.Entries().Titles())
+		require.Zero(t, a.Dropped())
+	})
+
+	t.Run("messages logged after Close are written synchronously", func(t *testing.T) {
+		o := xlog.NewObserver()
+		a := xlog.NewAsync(o, xlog.AsyncConfig{Size: 0, Policy: xlog.Block})
+		require.NoError(t, a.Close())
+		require.NoError(t, a.Close())
+
+		a.Warn(xlog.Messagef("late"))
+		require.Equal(t, []string{"late"}, o.Entries().Titles())
+	})
+
+	t.Run("package-level Close flushes the default logger", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, err := xlog.New(xlog.Config{
+			Format: xlog.FormatJSON,
+			Output: &buf,
+			Async:  &xlog.AsyncConfig{Size: 16, Policy: xlog.DropNewest},
+		})
+		require.NoError(t, err)
+
+		prev := xlog.SwapDefault(logger)
+		t.Cleanup(func() { xlog.SetDefault(prev) })
+
+		xlog.InfoString("buffered")
+		require.NoError(t, xlog.Close())
+
+		entries := decodeEntries(t, &buf)
+		require.Len(t, entries, 1)
+		require.Equal(t, "buffered", entries[0]["title"])
+		require.Contains(t, entries[0]["caller"], "xlog/async_test.go")
+	})
+
+	t.Run("Flush and Close reach the Async logger through wrappers", func(t *testing.T) {
+		o := xlog.NewObserver()
+		a := xlog.NewAsync(o, xlog.AsyncConfig{Size: 16, Policy: xlog.Block})
+		sampled := xlog.NewSampler(a, xlog.SamplingConfig{First: 10, Thereafter: 0, Interval: 0})
+
+		prev := xlog.SwapDefault(sampled)
+		t.Cleanup(func() { xlog.SetDefault(prev) })
+
+		xlog.InfoString("through the sampler")
+		require.NoError(t, xlog.Flush())
+		require.Equal(t, []string{"through the sampler"}, o.Entries().Titles())
+
+		named := xlog.Named("worker")
+		named.Info(xlog.Messagef("through a named logger"))
+		require.Implements(t, (*xlog.Syncer)(nil), named)
+		require.NoError(t, named.(xlog.Syncer).Sync())
+		require.Len(t, o.Entries(), 2)
+
+		ctxLog := xlog.WithContext(named, context.Background())
+		require.Implements(t, (*xlog.Closer)(nil), ctxLog)
+		require.NoError(t, ctxLog.(xlog.Closer).Close())
+
+		a.Info(xlog.Messagef("after close"))
+		require.Len(t, o.Entries(), 3)
+	})
+
+	t.Run("package-level Dropped counts the drops of the default logger", func(t *testing.T) {
+		g := newGatedLog()
+		a := xlog.NewAsync(g, xlog.AsyncConfig{Size: 2, Policy: xlog.DropNewest})
+
+		prev := xlog.SwapDefault(xlog.WithName(a, "worker"))
+		t.Cleanup(func() { xlog.SetDefault(prev) })
+
+		fill(t, a, 4)
+		require.Equal(t, uint64(2), xlog.Dropped())
+
+		close(g.gate)
+		require.NoError(t, xlog.Close())
+	})
+
+	t.Run("messages below the level of the next logger are not buffered", func(t *testing.T) {
+		resetLevels(t, "quiet")
+		xlog.SetLevelFor("quiet", xlog.WarnLevel)
+
+		o := xlog.NewObserver()
+		a := xlog.NewAsync(xlog.WithName(o, "quiet"), xlog.AsyncConfig{Size: 1, Policy: xlog.DropNewest})
+		t.Cleanup(func() { require.NoError(t, a.Close()) })
+
+		for i := 0; i < 10; i++ {
+			a.Debug(xlog.Messagef("skipped"))
+		}
+		a.Warn(xlog.Messagef("kept"))
+		require.NoError(t, a.Flush())
+
+		require.Equal(t, []string{"kept"}, o.Entries().Titles())
+		require.Zero(t, a.Dropped())
+	})
+
+	t.Run("flushing a synchronous default logger succeeds", func(t *testing.T) {
+		prev := xlog.SwapDefault(nil)
+		t.Cleanup(func() { xlog.SetDefault(prev) })
+		require.NoError(t, xlog.UseFormat(xlog.FormatPretty))
+
+		require.NoError(t, xlog.Flush())
+		require.NoError(t, xlog.Close())
+	})
+}
@@ -0,0 +1,69 @@
+package xlog
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Format is the encoding of the log output.
+type Format string
+
+// Log formats.
+const (
+	// FormatPretty is a human-readable format for development.
+	FormatPretty Format = "pretty"
+	// FormatJSON is a JSON format for log aggregation in production.
+	FormatJSON Format = "json"
+)
+
+// FormatEnv is the environment variable selecting the format of the
+// default logger. It defaults to FormatPretty.
+const FormatEnv = "XLOG_FORMAT"
+
+// Config configures a logger created with New.
+type Config struct {
+	// Format is the encoding of the output. Defaults to FormatPretty.
+	Format Format
+
+	// Output is where logs are written. Defaults to standard error for
+	// FormatPretty and standard output for FormatJSON.
+	Output io.Writer
+
+	// Async, if set, buffers messages and writes them in the background.
+	// Call Flush or Close on the logger, or the package-level functions if
+	// it is the default, before exiting.
+	Async *AsyncConfig
+}
+
+// New creates a standalone logger, e.g. to inject into a component instead
+// of using the package-level functions.
+func New(cfg Config) (Log, error) {
+	var (
+		l   Log
+		err error
+	)
+	switch cfg.Format {
+	case FormatJSON:
+		l, err = newJSONLogger(writeSyncer(cfg.Output, os.Stdout))
+	case FormatPretty, "":
+		l, err = newPrettyLogger(writeSyncer(cfg.Output, os.Stderr))
+	default:
+		return nil, fmt.Errorf("xlog: unknown format %q", cfg.Format)
+	}
+	if err != nil || cfg.Async == nil {
+		return l, err
+	}
+	return NewAsync(l, *cfg.Async), nil
+}
+
+// writeSyncer returns a goroutine-safe zapcore.WriteSyncer for w, or for
+// fallback if w is nil.
+func writeSyncer(w io.Writer, fallback *os.File) zapcore.WriteSyncer {
+	if w == nil {
+		return zapcore.Lock(fallback)
+	}
+	return zapcore.Lock(zapcore.AddSync(w))
+}
@@ -0,0 +1,185 @@
+package xlog
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// Keys of the trace fields added to messages logged with a context.
+const (
+	TraceIDKey = "trace_id"
+	SpanIDKey  = "span_id"
+)
+
+type contextKey int
+
+const (
+	dataKey contextKey = iota
+	fieldsKey
+	traceKey
+)
+
+type trace struct {
+	traceID string
+	spanID  string
+}
+
+// TraceExtractor returns the trace and span IDs carried by ctx, if any.
+type TraceExtractor func(ctx context.Context) (traceID, spanID string, ok bool)
+
+var traceExtractor atomic.Value
+
+func init() {
+	traceExtractor.Store(TraceExtractor(contextTrace))
+}
+
+// SetTraceExtractor replaces the function reading trace and span IDs from a
+// context, e.g. to read them from an OpenTelemetry span. A nil extractor
+// restores the default, which reads the IDs set with ContextWithTrace.
+func SetTraceExtractor(f TraceExtractor) {
+	if f == nil {
+		f = contextTrace
+	}
+	traceExtractor.Store(f)
+}
+
+// ContextWithTrace returns a copy of ctx carrying the given trace and span IDs.
+func ContextWithTrace(ctx context.Context, traceID, spanID string) context.Context {
+	return context.WithValue(ctx, traceKey, trace{traceID: traceID, spanID: spanID})
+}
+
+func contextTrace(ctx context.Context) (string, string, bool) {
+	t, ok := ctx.Value(traceKey).(trace)
+	return t.traceID, t.spanID, ok
+}
+
+// WithData returns a copy of ctx carrying data, which is added to every
+// message logged with the context. It is merged over the data already
+// attached to ctx.
+func WithData(ctx context.Context, data map[string]string) context.Context {
+	merged := make(map[string]string, len(data))
+	for k, v := range Data(ctx) {
+		merged[k] = v
+	}
+	for k, v := range data {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, dataKey, merged)
+}
+
+// Data returns the data attached to ctx with WithData.
+func Data(ctx context.Context) map[string]string {
+	data, _ := ctx.Value(dataKey).(map[string]string)
+	return data
+}
+
+// WithFields returns a copy of ctx carrying fields, which are added to every
+// message logged with the context after the fields already attached to ctx.
+func WithFields(ctx context.Context, fields ...Field) context.Context {
+	existing := Fields(ctx)
+	merged := make([]Field, 0, len(existing)+len(fields))
+	merged = append(merged, existing...)
+	merged = append(merged, fields...)
+	return context.WithValue(ctx, fieldsKey, merged)
+}
+
+// Fields returns the fields attached to ctx with WithFields.
+func Fields(ctx context.Context) []Field {
+	fields, _ := ctx.Value(fieldsKey).([]Field)
+	return fields
+}
+
+// contextMessage returns msg with the data, fields and trace of ctx added.
+// Data already set on msg takes precedence.
+func contextMessage(ctx context.Context, msg Message) Message {
+	if fields := Fields(ctx); len(fields) > 0 {
+		merged := make([]Field, 0, len(fields)+len(msg.Fields))
+		merged = append(merged, fields...)
+		msg.Fields = append(merged, msg.Fields...)
+	}
+
+	extract, _ := traceExtractor.Load().(TraceExtractor)
+	traceID, spanID, traced := extract(ctx)
+
+	data := Data(ctx)
+	if len(data) == 0 && !traced {
+		return msg
+	}
+
+	merged := make(map[string]string, len(data)+len(msg.Data)+2) //nolint:gomnd
+	for k, v := range data {
+		merged[k] = v
+	}
+	if traced {
+		merged[TraceIDKey] = traceID
+		merged[SpanIDKey] = spanID
+	}
+	for k, v := range msg.Data {
+		merged[k] = v
+	}
+	msg.Data = merged
+	return msg
+}
+
+type ctxLog struct {
+	log Log
+	ctx context.Context
+}
+
+// WithContext returns a logger adding the data, fields and trace of ctx to
+// every message logged through l. It forwards Sync and Close to l.
+func WithContext(l Log, ctx context.Context) Log {
+	return ctxLog{log: l, ctx: ctx}
+}
+
+func (c ctxLog) Info(msg Message) {
+	c.log.Info(contextMessage(c.ctx, msg))
+}
+
+func (c ctxLog) Warn(msg Message) {
+	c.log.Warn(contextMessage(c.ctx, msg))
+}
+
+func (c ctxLog) Error(msg Message) {
+	c.log.Error(contextMessage(c.ctx, msg))
+}
+
+func (c ctxLog) Debug(msg Message) {
+	c.log.Debug(contextMessage(c.ctx, msg))
+}
+
+func (c ctxLog) Sync() error {
+	return syncLog(c.log)
+}
+
+func (c ctxLog) Close() error {
+	return closeLog(c.log)
+}
+
+func (c ctxLog) unwrap() Log {
+	return c.log
+}
+
+func (c ctxLog) enabled(l Level) bool {
+	return logEnabled(c.log, l)
+}
+
+// InfoCtx logs an info message with the data, fields and trace of ctx.
+func InfoCtx(ctx context.Context, msg Message) {
+	root.Info(contextMessage(ctx, msg))
+}
+
+// WarnCtx logs a warning message with the data, fields and trace of ctx.
+func WarnCtx(ctx context.Context, msg Message) {
+	root.Warn(contextMessage(ctx, msg))
+}
+
+// ErrorCtx logs an error message with the data, fields and trace of ctx.
+func ErrorCtx(ctx context.Context, msg Message) {
+	root.Error(contextMessage(ctx, msg))
+}
+
+// DebugCtx logs a debug message with the data, fields and trace of ctx.
+func DebugCtx(ctx context.Context, msg Message) {
+	root.Debug(contextMessage(ctx, msg))
+}
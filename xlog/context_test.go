@@ -0,0 +1,76 @@
+package xlog_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/hardiksachan/x/xlog"
+	"github.com/stretchr/testify/require"
+)
+
+type traceIDs struct{}
+
+func TestContextLogging(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := xlog.New(xlog.Config{Format: xlog.FormatJSON, Output: &buf})
+	require.NoError(t, err)
+
+	t.Run("data attached to the context is logged", func(t *testing.T) {
+		ctx := xlog.WithData(context.Background(), map[string]string{"request_id": "r1"})
+		ctx = xlog.WithData(ctx, map[string]string{"user_id": "u1"})
+
+		xlog.WithContext(logger, ctx).Info(xlog.Message{
+			Title:   "created",
+			Details: "",
+			Data:    map[string]string{"user_id": "u2"},
+			Fields:  nil,
+		})
+
+		entries := decodeEntries(t, &buf)
+		require.Len(t, entries, 1)
+		require.Equal(t, "r1", entries[0]["request_id"])
+		require.Equal(t, "u2", entries[0]["user_id"])
+	})
+
+	t.Run("trace and span IDs are logged", func(t *testing.T) {
+		ctx := xlog.ContextWithTrace(context.Background(), "t1", "s1")
+
+		xlog.WithContext(logger, ctx).Warn(xlog.Messagef("slow"))
+
+		entries := decodeEntries(t, &buf)
+		require.Len(t, entries, 1)
+		require.Equal(t, "t1", entries[0][xlog.TraceIDKey])
+		require.Equal(t, "s1", entries[0][xlog.SpanIDKey])
+	})
+
+	t.Run("trace extractor can be replaced", func(t *testing.T) {
+		xlog.SetTraceExtractor(func(ctx context.Context) (string, string, bool) {
+			ids, ok := ctx.Value(traceIDs{}).([2]string)
+			return ids[0], ids[1], ok
+		})
+		t.Cleanup(func() { xlog.SetTraceExtractor(nil) })
+
+		ctx := context.WithValue(context.Background(), traceIDs{}, [2]string{"t2", "s2"})
+
+		xlog.WithContext(logger, ctx).Error(xlog.Messagef("failed"))
+
+		entries := decodeEntries(t, &buf)
+		require.Len(t, entries, 1)
+		require.Equal(t, "t2", entries[0][xlog.TraceIDKey])
+		require.Equal(t, "s2", entries[0][xlog.SpanIDKey])
+	})
+
+	t.Run("package-level functions log the context", func(t *testing.T) {
+		prev := xlog.SwapDefault(logger)
+		t.Cleanup(func() { xlog.SetDefault(prev) })
+
+		ctx := xlog.WithData(context.Background(), map[string]string{"request_id": "r2"})
+		xlog.InfoCtx(ctx, xlog.Messagef("hello"))
+
+		entries := decodeEntries(t, &buf)
+		require.Len(t, entries, 1)
+		require.Equal(t, "r2", entries[0]["request_id"])
+		require.Contains(t, entries[0]["caller"], "xlog/context_test.go")
+	})
+}
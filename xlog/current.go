@@ -1,102 +1,209 @@
 package xlog
 
 import (
-	"fmt"
+	"os"
 	"sync"
 )
 
 var (
 	once     sync.Once
+	mu       sync.RWMutex
 	instance Log
 )
 
 func currentLogger() Log {
 	once.Do(func() {
-		// TODO: initialise JSON currentLogger
-
-		pretty, err := newPrettyLogger()
+		logger, err := New(Config{
+			Format: Format(os.Getenv(FormatEnv)),
+			Output: nil,
+			Async:  nil,
+		})
+		if err != nil {
+			logger, err = New(Config{Format: FormatPretty, Output: nil, Async: nil})
+		}
 		if err != nil {
-			instance = newNoopLogger()
-		} else {
-			instance = pretty
+			logger = newNoopLogger()
 		}
+
+		mu.Lock()
+		if instance == nil {
+			instance = logger
+		}
+		mu.Unlock()
 	})
 
+	mu.RLock()
+	defer mu.RUnlock()
 	return instance
 }
 
+// SetDefault replaces the logger used by the package-level functions and
+// by Current. It is safe to call concurrently with logging. A nil logger
+// discards everything.
+func SetDefault(l Log) {
+	SwapDefault(l)
+}
+
+// SwapDefault replaces the default logger like SetDefault and returns the
+// previous one, so that it can be restored later.
+func SwapDefault(l Log) Log {
+	prev := currentLogger()
+	if forwardsToDefault(l) {
+		return prev
+	}
+	if l == nil {
+		l = newNoopLogger()
+	}
+
+	mu.Lock()
+	prev, instance = instance, l
+	mu.Unlock()
+	return prev
+}
+
+// UseFormat replaces the default logger with one writing in the given format.
+func UseFormat(format Format) error {
+	logger, err := New(Config{Format: format, Output: nil, Async: nil})
+	if err != nil {
+		return err
+	}
+
+	SetDefault(logger)
+	return nil
+}
+
+// forwardsToDefault reports whether l is a proxy for the default logger,
+// which must not become the default itself.
+func forwardsToDefault(l Log) bool {
+	if named, ok := l.(leveled); ok {
+		l = named.log
+	}
+	_, ok := l.(currentLog)
+	return ok
+}
+
+// currentLog forwards every message to the default logger at the time of
+// the call.
+type currentLog struct{}
+
+func (currentLog) Info(msg Message) {
+	currentLogger().Info(msg)
+}
+
+func (currentLog) Warn(msg Message) {
+	currentLogger().Warn(msg)
+}
+
+func (currentLog) Error(msg Message) {
+	currentLogger().Error(msg)
+}
+
+func (currentLog) Debug(msg Message) {
+	currentLogger().Debug(msg)
+}
+
+func (currentLog) Sync() error {
+	return syncLog(currentLogger())
+}
+
+func (currentLog) Close() error {
+	return closeLog(currentLogger())
+}
+
+func (currentLog) unwrap() Log {
+	return currentLogger()
+}
+
+func (currentLog) enabled(l Level) bool {
+	return logEnabled(currentLogger(), l)
+}
+
+// root is the default logger filtered by the global level.
+var root Log = leveled{name: "", log: currentLog{}}
+
+// Current returns a logger that forwards to whichever logger is the default
+// at the time of each call, dropping messages below the global level.
+// Components use it, or Named, when no logger is injected.
+func Current() Log {
+	return root
+}
+
 // Info logs an info message.
 func Info(msg Message) {
-	currentLogger().Info(msg)
+	root.Info(msg)
 }
 
 // InfoString logs an info message.
 func InfoString(msg string) {
-	currentLogger().Info(Message{
+	root.Info(Message{
 		Title:   msg,
 		Details: "",
 		Data:    nil,
+		Fields:  nil,
 	})
 }
 
 // Infof logs an info message.
 func Infof(format string, args ...interface{}) {
-	InfoString(fmt.Sprintf(format, args...))
+	root.Info(Messagef(format, args...))
 }
 
 // Warn logs a warning message.
 func Warn(msg Message) {
-	currentLogger().Warn(msg)
+	root.Warn(msg)
 }
 
 // WarnString logs a warning message.
 func WarnString(msg string) {
-	currentLogger().Warn(Message{
+	root.Warn(Message{
 		Title:   msg,
 		Details: "",
 		Data:    nil,
+		Fields:  nil,
 	})
 }
 
 // Warnf logs a warning message.
 func Warnf(format string, args ...interface{}) {
-	WarnString(fmt.Sprintf(format, args...))
+	root.Warn(Messagef(format, args...))
 }
 
 // Error logs an error message.
 func Error(msg Message) {
-	currentLogger().Error(msg)
+	root.Error(msg)
 }
 
 // ErrorString logs an error message.
 func ErrorString(msg string) {
-	currentLogger().Error(Message{
+	root.Error(Message{
 		Title:   msg,
 		Details: "",
 		Data:    nil,
+		Fields:  nil,
 	})
 }
 
 // Errorf logs an error message.
 func Errorf(format string, args ...interface{}) {
-	ErrorString(fmt.Sprintf(format, args...))
+	root.Error(Messagef(format, args...))
 }
 
 // Debug logs a debug message.
 func Debug(msg Message) {
-	currentLogger().Debug(msg)
+	root.Debug(msg)
 }
 
 // DebugString logs a debug message.
 func DebugString(msg string) {
-	currentLogger().Debug(Message{
+	root.Debug(Message{
 		Title:   msg,
 		Details: "",
 		Data:    nil,
+		Fields:  nil,
 	})
 }
 
 // Debugf logs a debug message.
 func Debugf(format string, args ...interface{}) {
-	DebugString(fmt.Sprintf(format, args...))
+	root.Debug(Messagef(format, args...))
 }
@@ -0,0 +1,47 @@
+package xlog_test
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	"github.com/hardiksachan/x/xlog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetDefault(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := xlog.New(xlog.Config{Format: xlog.FormatJSON, Output: &buf})
+	require.NoError(t, err)
+
+	current := xlog.Current()
+	prev := xlog.SwapDefault(logger)
+	t.Cleanup(func() { xlog.SetDefault(prev) })
+
+	xlog.Infof("hello %s", "world")
+	current.Info(xlog.Messagef("through %s", "current"))
+
+	entries := decodeEntries(t, &buf)
+	require.Len(t, entries, 2)
+	require.Equal(t, "hello world", entries[0]["title"])
+	require.Equal(t, "through current", entries[1]["title"])
+}
+
+func TestSetDefaultConcurrently(t *testing.T) {
+	prev := xlog.SwapDefault(nil)
+	t.Cleanup(func() { xlog.SetDefault(prev) })
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2) //nolint:gomnd
+		go func() {
+			defer wg.Done()
+			xlog.SetDefault(nil)
+		}()
+		go func() {
+			defer wg.Done()
+			xlog.DebugString("racing")
+		}()
+	}
+	wg.Wait()
+}
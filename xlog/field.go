@@ -0,0 +1,159 @@
+package xlog
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type fieldType uint8
+
+const (
+	skipField fieldType = iota
+	stringField
+	int64Field
+	boolField
+	durationField
+	timeField
+	errorField
+	anyField
+	callerField
+)
+
+// Bounds of the times that fit in UnixNano.
+var (
+	minTime = time.Unix(0, -1<<63)    //nolint:gomnd
+	maxTime = time.Unix(0, (1<<63)-1) //nolint:gomnd
+)
+
+// Field is a typed key-value pair attached to a message. Fields are created
+// with constructors such as Int or Err, and are encoded without being
+// converted to strings first.
+type Field struct {
+	Key string
+
+	typ     fieldType
+	integer int64
+	str     string
+	value   interface{}
+}
+
+// String returns a string field.
+func String(key, value string) Field {
+	return Field{Key: key, typ: stringField, integer: 0, str: value, value: nil}
+}
+
+// Int returns an int field.
+func Int(key string, value int) Field {
+	return Int64(key, int64(value))
+}
+
+// Int64 returns an int64 field.
+func Int64(key string, value int64) Field {
+	return Field{Key: key, typ: int64Field, integer: value, str: "", value: nil}
+}
+
+// Bool returns a bool field.
+func Bool(key string, value bool) Field {
+	var i int64
+	if value {
+		i = 1
+	}
+	return Field{Key: key, typ: boolField, integer: i, str: "", value: nil}
+}
+
+// Duration returns a duration field.
+func Duration(key string, value time.Duration) Field {
+	return Field{Key: key, typ: durationField, integer: int64(value), str: "", value: nil}
+}
+
+// Time returns a time field.
+func Time(key string, value time.Time) Field {
+	if value.Before(minTime) || value.After(maxTime) {
+		return Any(key, value)
+	}
+	return Field{Key: key, typ: timeField, integer: value.UnixNano(), str: "", value: value.Location()}
+}
+
+// Err returns a field with the key "error" holding err. A nil error is
+// omitted.
+func Err(err error) Field {
+	return NamedErr("error", err)
+}
+
+// NamedErr returns an error field with the given key. A nil error is omitted.
+func NamedErr(key string, err error) Field {
+	if err == nil {
+		return Field{Key: key, typ: skipField, integer: 0, str: "", value: nil}
+	}
+	return Field{Key: key, typ: errorField, integer: 0, str: "", value: err}
+}
+
+// Any returns a field holding an arbitrary value, which is encoded based on
+// its type. Prefer the typed constructors where possible.
+func Any(key string, value interface{}) Field {
+	return Field{Key: key, typ: anyField, integer: 0, str: "", value: value}
+}
+
+// callerAt returns a field recording the program counter of the call site,
+// for loggers that write messages away from where they were logged. It is
+// not encoded as a field.
+func callerAt(pc uintptr) Field {
+	return Field{Key: "", typ: callerField, integer: int64(pc), str: "", value: nil}
+}
+
+// messageCaller returns the call site recorded with callerAt, if any.
+func messageCaller(msg Message) (uintptr, bool) {
+	for _, f := range msg.Fields {
+		if f.typ == callerField {
+			return uintptr(f.integer), true
+		}
+	}
+	return 0, false
+}
+
+// Value returns the value of the field, as the type it was created from.
+// Time fields return a time.Time and omitted nil errors return nil.
+func (f Field) Value() interface{} {
+	switch f.typ {
+	case stringField:
+		return f.str
+	case int64Field:
+		return f.integer
+	case boolField:
+		return f.integer == 1
+	case durationField:
+		return time.Duration(f.integer)
+	case timeField:
+		loc, _ := f.value.(*time.Location)
+		return time.Unix(0, f.integer).In(loc)
+	case errorField, anyField:
+		return f.value
+	case skipField, callerField:
+	}
+	return nil
+}
+
+// zapField converts f to the equivalent zap field.
+func zapField(f Field) zap.Field {
+	switch f.typ {
+	case stringField:
+		return zap.String(f.Key, f.str)
+	case int64Field:
+		return zap.Int64(f.Key, f.integer)
+	case boolField:
+		return zap.Bool(f.Key, f.integer == 1)
+	case durationField:
+		return zap.Duration(f.Key, time.Duration(f.integer))
+	case timeField:
+		loc, _ := f.value.(*time.Location)
+		return zap.Time(f.Key, time.Unix(0, f.integer).In(loc))
+	case errorField:
+		err, _ := f.value.(error)
+		return zap.NamedError(f.Key, err)
+	case anyField:
+		return zap.Any(f.Key, f.value)
+	case skipField, callerField:
+	}
+	return zap.Skip()
+}
@@ -0,0 +1,109 @@
+package xlog_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/hardiksachan/x/xlog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFields(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := xlog.New(xlog.Config{Format: xlog.FormatJSON, Output: &buf})
+	require.NoError(t, err)
+
+	t.Run("fields are encoded with their type", func(t *testing.T) {
+		at := time.Date(2023, time.October, 1, 12, 0, 0, 0, time.UTC)
+
+		logger.Info(xlog.Messagef("processed").With(
+			xlog.String("name", "orders"),
+			xlog.Int("count", 3),
+			xlog.Bool("partial", true),
+			xlog.Duration("took", 1500*time.Millisecond),
+			xlog.Time("at", at),
+			xlog.Err(errors.New("boom")),
+			xlog.Any("tags", []string{"a", "b"}),
+		))
+
+		entries := decodeEntries(t, &buf)
+		require.Len(t, entries, 1)
+		entry := entries[0]
+
+		require.Equal(t, "orders", entry["name"])
+		require.Equal(t, float64(3), entry["count"])
+		require.Equal(t, true, entry["partial"])
+		require.Equal(t, 1.5, entry["took"])
+		require.Equal(t, "2023-10-01T12:00:00.000Z", entry["at"])
+		require.Equal(t, "boom", entry["error"])
+		require.Equal(t, []interface{}{"a", "b"}, entry["tags"])
+	})
+
+	t.Run("nil errors are omitted", func(t *testing.T) {
+		logger.Info(xlog.Messagef("ok").With(xlog.Err(nil)))
+
+		entries := decodeEntries(t, &buf)
+		require.Len(t, entries, 1)
+		require.NotContains(t, entries[0], "error")
+	})
+
+	t.Run("fields attached to the context are logged", func(t *testing.T) {
+		ctx := xlog.WithFields(context.Background(), xlog.Int("attempt", 2))
+
+		xlog.WithContext(logger, ctx).Warn(xlog.Messagef("retrying"))
+
+		entries := decodeEntries(t, &buf)
+		require.Len(t, entries, 1)
+		require.Equal(t, float64(2), entries[0]["attempt"])
+	})
+
+	t.Run("pretty logger writes structured fields", func(t *testing.T) {
+		var out bytes.Buffer
+		pretty, err := xlog.New(xlog.Config{Format: xlog.FormatPretty, Output: &out})
+		require.NoError(t, err)
+
+		pretty.Info(xlog.Messagef("processed").With(xlog.Int("count", 3)))
+
+		require.Contains(t, out.String(), "processed")
+		require.Contains(t, out.String(), `{"count": 3}`)
+		require.Contains(t, out.String(), "xlog/field_test.go")
+	})
+}
+
+func TestFieldAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("the race detector allocates")
+	}
+
+	logger, err := xlog.New(xlog.Config{Format: xlog.FormatJSON, Output: io.Discard})
+	require.NoError(t, err)
+
+	msg := xlog.Message{
+		Title:   "payment failed",
+		Details: "card declined",
+		Data:    map[string]string{"user_id": "42"},
+		Fields: []xlog.Field{
+			xlog.Int("attempt", 2),
+			xlog.String("card", "visa"),
+			xlog.Bool("retry", true),
+			xlog.Duration("elapsed", time.Second),
+			xlog.Err(errors.New("declined")),
+		},
+	}
+
+	t.Run("messages are encoded without allocating", func(t *testing.T) {
+		require.Zero(t, testing.AllocsPerRun(100, func() { logger.Info(msg) }))
+	})
+
+	t.Run("named loggers do not allocate", func(t *testing.T) {
+		prev := xlog.SwapDefault(logger)
+		t.Cleanup(func() { xlog.SetDefault(prev) })
+
+		named := xlog.Named("payments")
+		require.Zero(t, testing.AllocsPerRun(100, func() { named.Info(msg) }))
+	})
+}
@@ -0,0 +1,369 @@
+package xlog
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	backupTimeFormat = "20060102T150405.000000000"
+	compressSuffix   = ".gz"
+	filePerm         = 0o644
+	dirPerm          = 0o755
+)
+
+// FileConfig configures a FileWriter.
+type FileConfig struct {
+	// Filename is the file logs are written to. Rotated files are kept next
+	// to it, named with the time of the rotation.
+	Filename string
+
+	// MaxSize is the size in bytes at which the file is rotated. Zero
+	// disables size-based rotation.
+	MaxSize int64
+
+	// MaxAge is how long a file is written to before it is rotated. Zero
+	// disables age-based rotation.
+	MaxAge time.Duration
+
+	// MaxBackups is the number of rotated files to keep. Zero keeps all.
+	MaxBackups int
+
+	// Compress gzips rotated files.
+	Compress bool
+
+	// ReopenOnSIGHUP reopens the file when the process receives SIGHUP, for
+	// use with external tools such as logrotate.
+	ReopenOnSIGHUP bool
+}
+
+// FileWriter is an io.Writer writing to a file that is rotated by size and
+// age. Use it as the Output of a Config with either format. It is safe for
+// concurrent use.
+type FileWriter struct {
+	cfg FileConfig
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+	closed   bool
+
+	mill    sync.WaitGroup
+	millMu  sync.Mutex
+	signals chan os.Signal
+}
+
+// NewFileWriter opens the file, creating it and its directory if needed,
+// and appending to it otherwise.
+func NewFileWriter(cfg FileConfig) (*FileWriter, error) {
+	w := &FileWriter{
+		cfg:      cfg,
+		mu:       sync.Mutex{},
+		file:     nil,
+		size:     0,
+		openedAt: time.Time{},
+		closed:   false,
+		mill:     sync.WaitGroup{},
+		millMu:   sync.Mutex{},
+		signals:  nil,
+	}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+
+	if cfg.ReopenOnSIGHUP {
+		w.signals = make(chan os.Signal, 1)
+		signal.Notify(w.signals, syscall.SIGHUP)
+		go w.handleSignals(w.signals)
+	}
+	return w, nil
+}
+
+// Write writes p to the file, rotating it first if p would exceed MaxSize
+// or the file is older than MaxAge.
+func (w *FileWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed || w.file == nil {
+		return 0, fmt.Errorf("xlog: write to closed file %s", w.cfg.Filename)
+	}
+	if w.dueForRotation(int64(len(p))) {
+		if err := w.rotate(); err != nil {
+			if w.file == nil {
+				return 0, err
+			}
+			// Keep writing to the current file rather than losing p.
+			fmt.Fprintf(os.Stderr, "xlog: rotating %s: %v\n", w.cfg.Filename, err)
+		}
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Sync flushes the file to disk.
+func (w *FileWriter) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+	return w.file.Sync()
+}
+
+// Rotate moves the current file aside and starts a new one. If that fails,
+// writing continues to the current file.
+func (w *FileWriter) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return fmt.Errorf("xlog: rotate of closed file %s", w.cfg.Filename)
+	}
+	return w.rotate()
+}
+
+// Reopen closes and reopens the file, e.g. after it was moved by an
+// external tool.
+func (w *FileWriter) Reopen() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return fmt.Errorf("xlog: reopen of closed file %s", w.cfg.Filename)
+	}
+	return w.reopen()
+}
+
+// Close stops handling SIGHUP, waits for rotated files to be compressed and
+// closes the file. Writing, rotating or reopening it afterwards fails, and
+// closing it again does nothing.
+func (w *FileWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	if w.signals != nil {
+		signal.Stop(w.signals)
+		close(w.signals)
+		w.signals = nil
+	}
+	err := w.close()
+	w.mu.Unlock()
+
+	w.mill.Wait()
+	return err
+}
+
+func (w *FileWriter) handleSignals(signals <-chan os.Signal) {
+	for range signals {
+		if err := w.reopenOnSignal(); err != nil {
+			fmt.Fprintf(os.Stderr, "xlog: reopening %s: %v\n", w.cfg.Filename, err)
+		}
+	}
+}
+
+// reopenOnSignal reopens the file unless the writer was closed while the
+// signal was being handled.
+func (w *FileWriter) reopenOnSignal() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return nil
+	}
+	return w.reopen()
+}
+
+func (w *FileWriter) dueForRotation(n int64) bool {
+	if w.cfg.MaxSize > 0 && w.size > 0 && w.size+n > w.cfg.MaxSize {
+		return true
+	}
+	return w.cfg.MaxAge > 0 && time.Since(w.openedAt) >= w.cfg.MaxAge
+}
+
+func (w *FileWriter) open() error {
+	if err := os.MkdirAll(filepath.Dir(w.cfg.Filename), dirPerm); err != nil {
+		return fmt.Errorf("xlog: creating log directory: %w", err)
+	}
+
+	f, err := os.OpenFile(w.cfg.Filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, filePerm)
+	if err != nil {
+		return fmt.Errorf("xlog: opening log file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("xlog: opening log file: %w", err)
+	}
+
+	w.file = f
+	w.size = info.Size()
+	w.openedAt = time.Now()
+	return nil
+}
+
+func (w *FileWriter) reopen() error {
+	if err := w.close(); err != nil {
+		return err
+	}
+	return w.open()
+}
+
+func (w *FileWriter) close() error {
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+// rotate moves the file aside and opens a new one. If that fails, the
+// current file is reopened so that writing continues.
+func (w *FileWriter) rotate() error {
+	if err := w.close(); err != nil {
+		return w.reopenAfter(err)
+	}
+
+	backup := w.backupName(time.Now())
+	renameErr := os.Rename(w.cfg.Filename, backup)
+	if renameErr != nil && !os.IsNotExist(renameErr) {
+		return w.reopenAfter(fmt.Errorf("xlog: rotating log file: %w", renameErr))
+	}
+	if err := w.open(); err != nil {
+		if renameErr == nil {
+			_ = os.Rename(backup, w.cfg.Filename)
+		}
+		return w.reopenAfter(err)
+	}
+	if renameErr != nil {
+		// The file was removed externally, so there is nothing to mill.
+		return nil
+	}
+
+	w.mill.Add(1)
+	go func() {
+		defer w.mill.Done()
+		w.millBackups(backup)
+	}()
+	return nil
+}
+
+// reopenAfter reopens the file after a failed rotation and returns err.
+func (w *FileWriter) reopenAfter(err error) error {
+	if openErr := w.open(); openErr != nil {
+		return errors.Join(err, openErr)
+	}
+	return err
+}
+
+// backupName returns the name of the file rotated at t, e.g.
+// app-20231001T120000.000000000.log for app.log.
+func (w *FileWriter) backupName(t time.Time) string {
+	ext := filepath.Ext(w.cfg.Filename)
+	base := strings.TrimSuffix(w.cfg.Filename, ext)
+	return base + "-" + t.UTC().Format(backupTimeFormat) + ext
+}
+
+// millBackups compresses the new backup and removes the oldest backups
+// beyond MaxBackups. Errors are reported on standard error, since logging
+// them could recurse into the writer.
+func (w *FileWriter) millBackups(backup string) {
+	w.millMu.Lock()
+	defer w.millMu.Unlock()
+
+	if w.cfg.Compress {
+		if err := compressFile(backup); err != nil {
+			fmt.Fprintf(os.Stderr, "xlog: compressing %s: %v\n", backup, err)
+		}
+	}
+
+	if w.cfg.MaxBackups <= 0 {
+		return
+	}
+	backups, err := w.Backups()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "xlog: listing backups of %s: %v\n", w.cfg.Filename, err)
+		return
+	}
+	for len(backups) > w.cfg.MaxBackups {
+		if err := os.Remove(backups[0]); err != nil {
+			fmt.Fprintf(os.Stderr, "xlog: removing %s: %v\n", backups[0], err)
+		}
+		backups = backups[1:]
+	}
+}
+
+// Backups returns the paths of the rotated files, oldest first.
+func (w *FileWriter) Backups() ([]string, error) {
+	ext := filepath.Ext(w.cfg.Filename)
+	prefix := strings.TrimSuffix(filepath.Base(w.cfg.Filename), ext) + "-"
+
+	entries, err := os.ReadDir(filepath.Dir(w.cfg.Filename))
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []string
+	for _, e := range entries {
+		name := strings.TrimSuffix(e.Name(), compressSuffix)
+		if e.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ext)
+		if _, err := time.Parse(backupTimeFormat, stamp); err != nil {
+			continue
+		}
+		backups = append(backups, filepath.Join(filepath.Dir(w.cfg.Filename), e.Name()))
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return strings.TrimSuffix(backups[i], compressSuffix) < strings.TrimSuffix(backups[j], compressSuffix)
+	})
+	return backups, nil
+}
+
+// compressFile replaces name with a gzipped copy.
+func compressFile(name string) error {
+	src, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(name+compressSuffix, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, filePerm)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		_ = dst.Close()
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		_ = dst.Close()
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+	return os.Remove(name)
+}
@@ -0,0 +1,172 @@
+package xlog_test
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hardiksachan/x/xlog"
+	"github.com/stretchr/testify/require"
+)
+
+func readFile(t *testing.T, name string) string {
+	t.Helper()
+
+	f, err := os.Open(name)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(name, ".gz") {
+		gz, err := gzip.NewReader(f)
+		require.NoError(t, err)
+		r = gz
+	}
+
+	b, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return string(b)
+}
+
+func TestFileWriter(t *testing.T) {
+	t.Run("files are rotated by size and old backups removed", func(t *testing.T) {
+		name := filepath.Join(t.TempDir(), "logs", "app.log")
+		w, err := xlog.NewFileWriter(xlog.FileConfig{
+			Filename:       name,
+			MaxSize:        10,
+			MaxAge:         0,
+			MaxBackups:     2,
+			Compress:       false,
+			ReopenOnSIGHUP: false,
+		})
+		require.NoError(t, err)
+
+		for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+			_, err := w.Write([]byte(line))
+			require.NoError(t, err)
+		}
+		require.NoError(t, w.Close())
+
+		backups, err := w.Backups()
+		require.NoError(t, err)
+		require.Len(t, backups, 2)
+		require.Equal(t, "second\n", readFile(t, backups[0]))
+		require.Equal(t, "third\n", readFile(t, backups[1]))
+		require.Equal(t, "fourth\n", readFile(t, name))
+	})
+
+	t.Run("files are rotated by age and compressed", func(t *testing.T) {
+		name := filepath.Join(t.TempDir(), "app.log")
+		w, err := xlog.NewFileWriter(xlog.FileConfig{
+			Filename:       name,
+			MaxSize:        0,
+			MaxAge:         50 * time.Millisecond,
+			MaxBackups:     0,
+			Compress:       true,
+			ReopenOnSIGHUP: false,
+		})
+		require.NoError(t, err)
+
+		_, err = w.Write([]byte("old\n"))
+		require.NoError(t, err)
+		time.Sleep(60 * time.Millisecond)
+		_, err = w.Write([]byte("new\n"))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+
+		backups, err := w.Backups()
+		require.NoError(t, err)
+		require.Len(t, backups, 1)
+		require.True(t, strings.HasSuffix(backups[0], ".log.gz"))
+		require.Equal(t, "old\n", readFile(t, backups[0]))
+		require.Equal(t, "new\n", readFile(t, name))
+	})
+
+	t.Run("existing files are appended to and can be reopened", func(t *testing.T) {
+		name := filepath.Join(t.TempDir(), "app.log")
+		require.NoError(t, os.WriteFile(name, []byte("existing\n"), 0o600))
+
+		w, err := xlog.NewFileWriter(xlog.FileConfig{Filename: name})
+		require.NoError(t, err)
+
+		_, err = w.Write([]byte("appended\n"))
+		require.NoError(t, err)
+
+		require.NoError(t, os.Rename(name, name+".1"))
+		require.NoError(t, w.Reopen())
+
+		_, err = w.Write([]byte("reopened\n"))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+
+		require.Equal(t, "existing\nappended\n", readFile(t, name+".1"))
+		require.Equal(t, "reopened\n", readFile(t, name))
+
+		_, err = w.Write([]byte("closed\n"))
+		require.Error(t, err)
+	})
+
+	t.Run("closed writers are not reopened", func(t *testing.T) {
+		name := filepath.Join(t.TempDir(), "app.log")
+		w, err := xlog.NewFileWriter(xlog.FileConfig{Filename: name})
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		require.NoError(t, os.Remove(name))
+
+		require.Error(t, w.Reopen())
+		require.Error(t, w.Rotate())
+		_, err = w.Write([]byte("closed\n"))
+		require.Error(t, err)
+		require.NoError(t, w.Close())
+
+		_, err = os.Stat(name)
+		require.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("writing continues after a failed rotation", func(t *testing.T) {
+		// The backup name of a file with the longest allowed name is too long.
+		name := filepath.Join(t.TempDir(), strings.Repeat("a", 251)+".log")
+		w, err := xlog.NewFileWriter(xlog.FileConfig{
+			Filename:       name,
+			MaxSize:        10,
+			MaxAge:         0,
+			MaxBackups:     0,
+			Compress:       false,
+			ReopenOnSIGHUP: false,
+		})
+		require.NoError(t, err)
+
+		_, err = w.Write([]byte("first\n"))
+		require.NoError(t, err)
+		require.Error(t, w.Rotate())
+		_, err = w.Write([]byte("second\n"))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+
+		backups, err := w.Backups()
+		require.NoError(t, err)
+		require.Empty(t, backups)
+		require.Equal(t, "first\nsecond\n", readFile(t, name))
+	})
+
+	t.Run("loggers write to the file", func(t *testing.T) {
+		name := filepath.Join(t.TempDir(), "app.log")
+		w, err := xlog.NewFileWriter(xlog.FileConfig{Filename: name})
+		require.NoError(t, err)
+
+		for _, format := range []xlog.Format{xlog.FormatJSON, xlog.FormatPretty} {
+			logger, err := xlog.New(xlog.Config{Format: format, Output: w})
+			require.NoError(t, err)
+			logger.Info(xlog.Messagef("written as %s", format))
+		}
+		require.NoError(t, w.Close())
+
+		content := readFile(t, name)
+		require.Contains(t, content, `"title":"written as json"`)
+		require.Contains(t, content, "written as pretty")
+	})
+}
@@ -0,0 +1,73 @@
+//go:build unix
+
+package xlog_test
+
+import (
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/hardiksachan/x/xlog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileWriterSIGHUP(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "app.log")
+	w, err := xlog.NewFileWriter(xlog.FileConfig{
+		Filename:       name,
+		MaxSize:        0,
+		MaxAge:         0,
+		MaxBackups:     0,
+		Compress:       false,
+		ReopenOnSIGHUP: true,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, w.Close()) })
+
+	require.NoError(t, os.Rename(name, name+".1"))
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(name)
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestFileWriterConcurrentClose(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "app.log")
+	w, err := xlog.NewFileWriter(xlog.FileConfig{
+		Filename:       name,
+		MaxSize:        0,
+		MaxAge:         0,
+		MaxBackups:     0,
+		Compress:       false,
+		ReopenOnSIGHUP: true,
+	})
+	require.NoError(t, err)
+
+	// Keep SIGHUP from killing the test if Close stops the writer's
+	// notification before the signal is delivered.
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGHUP)
+	defer signal.Stop(sig)
+
+	// Close while the signal may be being handled.
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			require.NoError(t, w.Close())
+		}()
+	}
+	wg.Wait()
+
+	require.Error(t, w.Reopen())
+	<-sig
+}
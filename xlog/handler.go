@@ -0,0 +1,50 @@
+package xlog
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// levelRequest changes the global level, or the level of a name if Name is
+// set. A nil Level removes the override of the name.
+type levelRequest struct {
+	Name  string `json:"name"`
+	Level *Level `json:"level"`
+}
+
+// LevelHandler returns an HTTP handler that reports the configured levels on
+// GET, and changes them on PUT with a JSON body such as {"level": "info"} or
+// {"name": "outbox", "level": "debug"}. A PUT with a name and a null level
+// removes the override for the name. Both respond with the resulting levels.
+func LevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var req levelRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			switch {
+			case req.Name != "" && req.Level == nil:
+				ResetLevelFor(req.Name)
+			case req.Name != "":
+				SetLevelFor(req.Name, *req.Level)
+			case req.Level != nil:
+				SetLevel(*req.Level)
+			default:
+				http.Error(w, "xlog: level is required", http.StatusBadRequest)
+				return
+			}
+		default:
+			w.Header().Set("Allow", "GET, PUT")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(currentLevels())
+	})
+}
@@ -0,0 +1,48 @@
+package xlog
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Keys of the entry itself in the JSON format.
+const (
+	timestampKey = "timestamp"
+	titleKey     = "title"
+)
+
+// reservedKeys are the keys zap writes for the entry itself. Data and fields
+// with these keys are prefixed so that no key appears twice in an entry.
+var reservedKeys = map[string]bool{
+	timestampKey: true,
+	titleKey:     true,
+	"level":      true,
+	"caller":     true,
+	"logger":     true,
+	"stacktrace": true,
+	detailsKey:   true,
+}
+
+// entryKey returns key, prefixed with "field." if it is reserved.
+func entryKey(key string) string {
+	if reservedKeys[key] {
+		return "field." + key
+	}
+	return key
+}
+
+func newJSONLogger(w zapcore.WriteSyncer) (Log, error) {
+	encoder := zap.NewProductionEncoderConfig()
+	encoder.TimeKey = timestampKey
+	encoder.MessageKey = titleKey
+	encoder.EncodeTime = zapcore.ISO8601TimeEncoder
+	encoder.EncodeCaller = encodeCaller
+
+	core := zapcore.NewCore(
+		zapcore.NewJSONEncoder(encoder),
+		w,
+		zapcore.DebugLevel,
+	)
+
+	return &zapLogger{zap.New(core)}, nil
+}
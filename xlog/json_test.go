@@ -0,0 +1,82 @@
+package xlog_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hardiksachan/x/xlog"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeEntries(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+
+	var entries []map[string]interface{}
+	decoder := json.NewDecoder(buf)
+	for decoder.More() {
+		var entry map[string]interface{}
+		require.NoError(t, decoder.Decode(&entry))
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestJSONLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := xlog.New(xlog.Config{Format: xlog.FormatJSON, Output: &buf})
+	require.NoError(t, err)
+
+	logger.Warn(xlog.Message{
+		Title:   "payment failed",
+		Details: "card declined",
+		Data:    map[string]string{"order_id": "42"},
+		Fields:  nil,
+	})
+
+	entries := decodeEntries(t, &buf)
+	require.Len(t, entries, 1)
+	entry := entries[0]
+
+	require.Equal(t, "warn", entry["level"])
+	require.Equal(t, "payment failed", entry["title"])
+	require.Equal(t, "card declined", entry["details"])
+	require.Equal(t, "42", entry["order_id"])
+	require.Contains(t, entry["caller"], "xlog/json_test.go")
+	require.NotEmpty(t, entry["timestamp"])
+}
+
+func TestJSONLoggerReservedKeys(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := xlog.New(xlog.Config{Format: xlog.FormatJSON, Output: &buf})
+	require.NoError(t, err)
+
+	logger.Warn(xlog.Message{
+		Title:   "payment failed",
+		Details: "card declined",
+		Data:    map[string]string{"level": "x", "title": "y"},
+		Fields:  []xlog.Field{xlog.String("caller", "z"), xlog.Int("timestamp", 1)},
+	})
+
+	raw := buf.String()
+	for _, key := range []string{"level", "title", "caller", "timestamp"} {
+		require.Equal(t, 1, strings.Count(raw, `"`+key+`":`), key)
+	}
+
+	entries := decodeEntries(t, &buf)
+	require.Len(t, entries, 1)
+	entry := entries[0]
+
+	require.Equal(t, "warn", entry["level"])
+	require.Equal(t, "payment failed", entry["title"])
+	require.Equal(t, "x", entry["field.level"])
+	require.Equal(t, "y", entry["field.title"])
+	require.Equal(t, "z", entry["field.caller"])
+	require.Equal(t, 1.0, entry["field.timestamp"])
+}
+
+func TestNew(t *testing.T) {
+	_, err := xlog.New(xlog.Config{Format: "xml", Output: nil})
+	require.Error(t, err)
+}
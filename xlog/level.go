@@ -0,0 +1,238 @@
+package xlog
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Level is the severity of a message.
+type Level int8
+
+// Log levels, from the most to the least verbose.
+const (
+	DebugLevel Level = iota - 1
+	InfoLevel
+	WarnLevel
+	ErrorLevel
+)
+
+// LevelEnv is the environment variable configuring levels at startup, as a
+// comma-separated list of a minimum level and name=level overrides, e.g.
+// "info,outbox=debug".
+const LevelEnv = "XLOG_LEVEL"
+
+var levelNames = map[Level]string{
+	DebugLevel: "debug",
+	InfoLevel:  "info",
+	WarnLevel:  "warn",
+	ErrorLevel: "error",
+}
+
+// String returns the lower-case name of the level.
+func (l Level) String() string {
+	if name, ok := levelNames[l]; ok {
+		return name
+	}
+	return fmt.Sprintf("Level(%d)", l)
+}
+
+// MarshalText encodes the level as its name.
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText decodes a level from its name.
+func (l *Level) UnmarshalText(text []byte) error {
+	level, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*l = level
+	return nil
+}
+
+// ParseLevel returns the level with the given name, ignoring case.
+func ParseLevel(name string) (Level, error) {
+	for level, n := range levelNames {
+		if strings.EqualFold(n, strings.TrimSpace(name)) {
+			return level, nil
+		}
+	}
+	return 0, fmt.Errorf("xlog: unknown level %q", name)
+}
+
+var levels = struct {
+	sync.RWMutex
+	min   Level
+	names map[string]Level
+}{
+	min:   DebugLevel,
+	names: map[string]Level{},
+}
+
+func init() {
+	if spec := os.Getenv(LevelEnv); spec != "" {
+		_ = SetLevels(spec)
+	}
+}
+
+// SetLevel sets the minimum level of loggers without an override. It
+// defaults to DebugLevel.
+func SetLevel(l Level) {
+	levels.Lock()
+	levels.min = l
+	levels.Unlock()
+}
+
+// SetLevelFor overrides the minimum level of the loggers with the given name
+// and of their descendants, e.g. "outbox" also applies to "outbox.poller".
+func SetLevelFor(name string, l Level) {
+	levels.Lock()
+	levels.names[name] = l
+	levels.Unlock()
+}
+
+// ResetLevelFor removes the override for the given name.
+func ResetLevelFor(name string) {
+	levels.Lock()
+	delete(levels.names, name)
+	levels.Unlock()
+}
+
+// SetLevels applies a specification in the format of LevelEnv. Nothing is
+// changed if it is invalid.
+func SetLevels(spec string) error {
+	var (
+		minLevel  *Level
+		overrides = map[string]Level{}
+	)
+	for _, part := range strings.Split(spec, ",") {
+		name, value, named := strings.Cut(part, "=")
+		if !named {
+			value = name
+		}
+
+		level, err := ParseLevel(value)
+		if err != nil {
+			return err
+		}
+
+		if named {
+			overrides[strings.TrimSpace(name)] = level
+		} else {
+			minLevel = &level
+		}
+	}
+
+	if minLevel != nil {
+		SetLevel(*minLevel)
+	}
+	for name, level := range overrides {
+		SetLevelFor(name, level)
+	}
+	return nil
+}
+
+// LevelFor returns the minimum level of the logger with the given name,
+// which is the override of the name or of its closest ancestor, or the
+// global level.
+func LevelFor(name string) Level {
+	levels.RLock()
+	defer levels.RUnlock()
+
+	for name != "" {
+		if level, ok := levels.names[name]; ok {
+			return level
+		}
+		i := strings.LastIndexByte(name, '.')
+		if i < 0 {
+			break
+		}
+		name = name[:i]
+	}
+	return levels.min
+}
+
+// Enabled reports whether the logger with the given name logs messages at
+// the given level. The empty name is the default logger.
+func Enabled(name string, l Level) bool {
+	return l >= LevelFor(name)
+}
+
+// levelState is a snapshot of the configured levels.
+type levelState struct {
+	Level  Level            `json:"level"`
+	Levels map[string]Level `json:"levels"`
+}
+
+func currentLevels() levelState {
+	levels.RLock()
+	defer levels.RUnlock()
+
+	names := make(map[string]Level, len(levels.names))
+	for name, level := range levels.names {
+		names[name] = level
+	}
+	return levelState{Level: levels.min, Levels: names}
+}
+
+// leveled drops messages below the level of its name.
+type leveled struct {
+	name string
+	log  Log
+}
+
+// WithName returns a logger that forwards to l the messages at or above the
+// level configured for name. It forwards Sync and Close to l.
+func WithName(l Log, name string) Log {
+	return leveled{name: name, log: l}
+}
+
+// Named returns a logger with the given name that forwards to the default
+// logger. Its level can be changed with SetLevelFor. Sync and Close act on
+// the default logger.
+func Named(name string) Log {
+	return WithName(currentLog{}, name)
+}
+
+func (l leveled) Info(msg Message) {
+	if Enabled(l.name, InfoLevel) {
+		l.log.Info(msg)
+	}
+}
+
+func (l leveled) Warn(msg Message) {
+	if Enabled(l.name, WarnLevel) {
+		l.log.Warn(msg)
+	}
+}
+
+func (l leveled) Error(msg Message) {
+	if Enabled(l.name, ErrorLevel) {
+		l.log.Error(msg)
+	}
+}
+
+func (l leveled) Debug(msg Message) {
+	if Enabled(l.name, DebugLevel) {
+		l.log.Debug(msg)
+	}
+}
+
+func (l leveled) Sync() error {
+	return syncLog(l.log)
+}
+
+func (l leveled) Close() error {
+	return closeLog(l.log)
+}
+
+func (l leveled) unwrap() Log {
+	return l.log
+}
+
+func (l leveled) enabled(level Level) bool {
+	return Enabled(l.name, level) && logEnabled(l.log, level)
+}
@@ -0,0 +1,117 @@
+package xlog_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hardiksachan/x/xlog"
+	"github.com/stretchr/testify/require"
+)
+
+func resetLevels(t *testing.T, names ...string) {
+	t.Helper()
+
+	t.Cleanup(func() {
+		xlog.SetLevel(xlog.DebugLevel)
+		for _, name := range names {
+			xlog.ResetLevelFor(name)
+		}
+	})
+}
+
+func TestLevels(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := xlog.New(xlog.Config{Format: xlog.FormatJSON, Output: &buf})
+	require.NoError(t, err)
+
+	prev := xlog.SwapDefault(logger)
+	t.Cleanup(func() { xlog.SetDefault(prev) })
+
+	t.Run("messages below the global level are dropped", func(t *testing.T) {
+		resetLevels(t)
+		xlog.SetLevel(xlog.WarnLevel)
+
+		xlog.DebugString("debug")
+		xlog.InfoString("info")
+		xlog.WarnString("warn")
+
+		entries := decodeEntries(t, &buf)
+		require.Len(t, entries, 1)
+		require.Equal(t, "warn", entries[0]["title"])
+	})
+
+	t.Run("names override the global level", func(t *testing.T) {
+		resetLevels(t, "outbox")
+		xlog.SetLevel(xlog.InfoLevel)
+		xlog.SetLevelFor("outbox", xlog.DebugLevel)
+
+		xlog.Named("outbox.poller").Debug(xlog.Messagef("polled"))
+		xlog.Named("inbox").Debug(xlog.Messagef("dropped"))
+
+		entries := decodeEntries(t, &buf)
+		require.Len(t, entries, 1)
+		require.Equal(t, "polled", entries[0]["title"])
+	})
+
+	t.Run("levels are parsed from a specification", func(t *testing.T) {
+		resetLevels(t, "rabbitmq")
+		require.NoError(t, xlog.SetLevels("error, rabbitmq=info"))
+
+		require.Equal(t, xlog.ErrorLevel, xlog.LevelFor(""))
+		require.Equal(t, xlog.InfoLevel, xlog.LevelFor("rabbitmq"))
+		require.False(t, xlog.Enabled("rabbitmq", xlog.DebugLevel))
+
+		require.Error(t, xlog.SetLevels("info,outbox=loud"))
+		require.Equal(t, xlog.ErrorLevel, xlog.LevelFor("outbox"))
+	})
+}
+
+func TestLevelHandler(t *testing.T) {
+	resetLevels(t, "outbox")
+	handler := xlog.LevelHandler()
+
+	serve := func(method, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, "/log/level", strings.NewReader(body)))
+		return rec
+	}
+
+	decode := func(rec *httptest.ResponseRecorder) map[string]interface{} {
+		var state map[string]interface{}
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&state))
+		return state
+	}
+
+	t.Run("levels are changed with PUT", func(t *testing.T) {
+		rec := serve(http.MethodPut, `{"level": "warn"}`)
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, "warn", decode(rec)["level"])
+
+		rec = serve(http.MethodPut, `{"name": "outbox", "level": "debug"}`)
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, xlog.DebugLevel, xlog.LevelFor("outbox"))
+
+		rec = serve(http.MethodGet, "")
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, map[string]interface{}{
+			"level":  "warn",
+			"levels": map[string]interface{}{"outbox": "debug"},
+		}, decode(rec))
+	})
+
+	t.Run("overrides are removed with a null level", func(t *testing.T) {
+		rec := serve(http.MethodPut, `{"name": "outbox", "level": null}`)
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, xlog.WarnLevel, xlog.LevelFor("outbox"))
+	})
+
+	t.Run("invalid requests are rejected", func(t *testing.T) {
+		require.Equal(t, http.StatusBadRequest, serve(http.MethodPut, `{"level": "loud"}`).Code)
+		require.Equal(t, http.StatusBadRequest, serve(http.MethodPut, `{}`).Code)
+		require.Equal(t, http.StatusMethodNotAllowed, serve(http.MethodPost, "").Code)
+	})
+}
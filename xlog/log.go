@@ -1,11 +1,33 @@
 // Package xlog provides logging functions.
 package xlog
 
-// Message is a log message.
+import "fmt"
+
+// Message is a log message. Data holds string values and is kept for
+// compatibility; prefer typed Fields.
 type Message struct {
 	Title   string
 	Details string
 	Data    map[string]string
+	Fields  []Field
+}
+
+// Messagef returns a message with the formatted title.
+func Messagef(format string, args ...interface{}) Message {
+	return Message{
+		Title:   fmt.Sprintf(format, args...),
+		Details: "",
+		Data:    nil,
+		Fields:  nil,
+	}
+}
+
+// With returns a copy of the message with fields appended.
+func (m Message) With(fields ...Field) Message {
+	merged := make([]Field, 0, len(m.Fields)+len(fields))
+	merged = append(merged, m.Fields...)
+	m.Fields = append(merged, fields...)
+	return m
 }
 
 // Log is a logger.
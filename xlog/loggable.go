@@ -0,0 +1,59 @@
+package xlog
+
+import (
+	"context"
+	"errors"
+)
+
+// Loggable is implemented by errors that describe how they are logged, such
+// as *xerrors.Error.
+type Loggable interface {
+	LogMessage() (Level, Message)
+}
+
+// ErrorMessage returns the level and message for logging err. Errors that
+// are or wrap a Loggable describe themselves; others are logged at
+// ErrorLevel with the error as the title.
+func ErrorMessage(err error) (Level, Message) {
+	var loggable Loggable
+	if errors.As(err, &loggable) {
+		return loggable.LogMessage()
+	}
+	return ErrorLevel, Message{
+		Title:   err.Error(),
+		Details: "",
+		Data:    nil,
+		Fields:  nil,
+	}
+}
+
+// LogAt logs msg to l at the given level.
+func LogAt(l Log, level Level, msg Message) {
+	switch level {
+	case DebugLevel:
+		l.Debug(msg)
+	case InfoLevel:
+		l.Info(msg)
+	case WarnLevel:
+		l.Warn(msg)
+	case ErrorLevel:
+		l.Error(msg)
+	default:
+		l.Error(msg)
+	}
+}
+
+// LogError logs err to l with the level and message from ErrorMessage. A nil
+// error is not logged.
+func LogError(l Log, err error) {
+	if err == nil {
+		return
+	}
+	level, msg := ErrorMessage(err)
+	LogAt(l, level, msg)
+}
+
+// LogErrorCtx is LogError with the data, fields and trace of ctx.
+func LogErrorCtx(ctx context.Context, l Log, err error) {
+	LogError(WithContext(l, ctx), err)
+}
@@ -0,0 +1,33 @@
+package xlog_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/hardiksachan/x/xlog"
+	"github.com/stretchr/testify/require"
+)
+
+type quietError struct{}
+
+func (quietError) Error() string { return "quiet" }
+
+func (quietError) LogMessage() (xlog.Level, xlog.Message) {
+	return xlog.DebugLevel, xlog.Messagef("expected failure")
+}
+
+func TestLogError(t *testing.T) {
+	o := xlog.NewObserver()
+
+	xlog.LogError(o, errors.New("boom"))
+	xlog.LogError(o, fmt.Errorf("wrapped: %w", quietError{}))
+	xlog.LogError(o, nil)
+
+	entries := o.Entries()
+	require.Len(t, entries, 2)
+	require.Equal(t, xlog.ErrorLevel, entries[0].Level)
+	require.Equal(t, "boom", entries[0].Title)
+	require.Equal(t, xlog.DebugLevel, entries[1].Level)
+	require.Equal(t, "expected failure", entries[1].Title)
+}
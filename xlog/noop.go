@@ -13,3 +13,9 @@ func (z *noopLogger) Debug(_ Message) {}
 func newNoopLogger() Log {
 	return &noopLogger{}
 }
+
+// Nop returns a Log that discards every message. It is what SetDefault
+// installs when given nil.
+func Nop() Log {
+	return newNoopLogger()
+}
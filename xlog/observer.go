@@ -0,0 +1,162 @@
+package xlog
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Entry is a message recorded by an Observer.
+type Entry struct {
+	Level   Level
+	Title   string
+	Details string
+	Data    map[string]string
+	Fields  []Field
+}
+
+// Value returns the value of the data entry or field with the given key.
+// Fields take precedence over data.
+func (e Entry) Value(key string) (interface{}, bool) {
+	for i := len(e.Fields) - 1; i >= 0; i-- {
+		if e.Fields[i].Key == key {
+			return e.Fields[i].Value(), true
+		}
+	}
+	v, ok := e.Data[key]
+	return v, ok
+}
+
+// String formats the entry for test failure output.
+func (e Entry) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %q", e.Level, e.Title)
+	if e.Details != "" {
+		fmt.Fprintf(&b, " details=%q", e.Details)
+	}
+	for k, v := range e.Data {
+		fmt.Fprintf(&b, " %s=%v", k, v)
+	}
+	for _, f := range e.Fields {
+		fmt.Fprintf(&b, " %s=%v", f.Key, f.Value())
+	}
+	return b.String()
+}
+
+// Entries is a list of recorded entries with query helpers.
+type Entries []Entry
+
+// ByLevel returns the entries at the given level.
+func (es Entries) ByLevel(l Level) Entries {
+	return es.filter(func(e Entry) bool { return e.Level == l })
+}
+
+// Containing returns the entries whose title or details contain s.
+func (es Entries) Containing(s string) Entries {
+	return es.filter(func(e Entry) bool {
+		return strings.Contains(e.Title, s) || strings.Contains(e.Details, s)
+	})
+}
+
+// WithField returns the entries with a data entry or field with the given
+// key and value. Values are compared by their formatted value, so
+// WithField("attempt", 2) matches both Int("attempt", 2) and "2" in Data.
+func (es Entries) WithField(key string, value interface{}) Entries {
+	want := fmt.Sprint(value)
+	return es.filter(func(e Entry) bool {
+		v, ok := e.Value(key)
+		return ok && fmt.Sprint(v) == want
+	})
+}
+
+// Titles returns the titles of the entries.
+func (es Entries) Titles() []string {
+	titles := make([]string, 0, len(es))
+	for _, e := range es {
+		titles = append(titles, e.Title)
+	}
+	return titles
+}
+
+func (es Entries) filter(match func(Entry) bool) Entries {
+	var matched Entries
+	for _, e := range es {
+		if match(e) {
+			matched = append(matched, e)
+		}
+	}
+	return matched
+}
+
+// Observer is a Log that records every message in memory, so tests can
+// assert on what was logged. It is safe for concurrent use. Package
+// xlogtest installs and asserts on observers in tests.
+type Observer struct {
+	mu      sync.Mutex
+	entries Entries
+}
+
+// NewObserver creates an empty Observer.
+func NewObserver() *Observer {
+	return &Observer{
+		mu:      sync.Mutex{},
+		entries: nil,
+	}
+}
+
+func (o *Observer) record(l Level, msg Message) {
+	var fields []Field
+	for _, f := range msg.Fields {
+		if f.typ != callerField {
+			fields = append(fields, f)
+		}
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.entries = append(o.entries, Entry{
+		Level:   l,
+		Title:   msg.Title,
+		Details: msg.Details,
+		Data:    msg.Data,
+		Fields:  fields,
+	})
+}
+
+// Info records an info message.
+func (o *Observer) Info(msg Message) {
+	o.record(InfoLevel, msg)
+}
+
+// Warn records a warning message.
+func (o *Observer) Warn(msg Message) {
+	o.record(WarnLevel, msg)
+}
+
+// Error records an error message.
+func (o *Observer) Error(msg Message) {
+	o.record(ErrorLevel, msg)
+}
+
+// Debug records a debug message.
+func (o *Observer) Debug(msg Message) {
+	o.record(DebugLevel, msg)
+}
+
+// Entries returns a copy of the recorded entries, oldest first.
+func (o *Observer) Entries() Entries {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	entries := make(Entries, len(o.entries))
+	copy(entries, o.entries)
+	return entries
+}
+
+// Reset discards the recorded entries.
+func (o *Observer) Reset() {
+	o.mu.Lock()
+	o.entries = nil
+	o.mu.Unlock()
+}
@@ -0,0 +1,40 @@
+package xlog_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hardiksachan/x/xlog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObserver(t *testing.T) {
+	t.Run("entries are recorded and queried", func(t *testing.T) {
+		o := xlog.NewObserver()
+
+		o.Info(xlog.Messagef("started"))
+		o.Warn(xlog.Messagef("retrying").With(xlog.Int("attempt", 2), xlog.Duration("after", time.Second)))
+		o.Error(xlog.Message{
+			Title:   "failed",
+			Details: "connection reset",
+			Data:    map[string]string{"attempt": "3"},
+			Fields:  []xlog.Field{xlog.Err(errors.New("boom"))},
+		})
+
+		entries := o.Entries()
+		require.Equal(t, []string{"started", "retrying", "failed"}, entries.Titles())
+		require.Equal(t, []string{"retrying"}, entries.ByLevel(xlog.WarnLevel).Titles())
+		require.Equal(t, []string{"failed"}, entries.Containing("reset").Titles())
+		require.Equal(t, []string{"retrying"}, entries.WithField("attempt", 2).Titles())
+		require.Equal(t, []string{"failed"}, entries.WithField("attempt", 3).Titles())
+		require.Equal(t, []string{"failed"}, entries.WithField("error", "boom").Titles())
+
+		after, ok := entries[1].Value("after")
+		require.True(t, ok)
+		require.Equal(t, time.Second, after)
+
+		o.Reset()
+		require.Empty(t, o.Entries())
+	})
+}
@@ -2,34 +2,19 @@ package xlog
 
 import (
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
-type prettyLogger struct {
-	sugar *zap.SugaredLogger
-}
-
-func (z *prettyLogger) Info(msg Message) {
-	z.sugar.Info(msg)
-}
-
-func (z *prettyLogger) Warn(msg Message) {
-	z.sugar.Warn(msg)
-}
-
-func (z *prettyLogger) Error(msg Message) {
-	z.sugar.Error(msg)
-}
-
-func (z *prettyLogger) Debug(msg Message) {
-	z.sugar.Debug(msg)
-}
+func newPrettyLogger(w zapcore.WriteSyncer) (Log, error) {
+	encoder := zap.NewDevelopmentEncoderConfig()
+	encoder.EncodeCaller = encodeCaller
 
-func newPrettyLogger() (Log, error) {
-	logger, err := zap.NewDevelopment()
-	if err != nil {
-		return nil, err
-	}
+	core := zapcore.NewCore(
+		zapcore.NewConsoleEncoder(encoder),
+		w,
+		zapcore.DebugLevel,
+	)
+	logger := zap.New(core, zap.Development(), zap.AddStacktrace(zapcore.WarnLevel))
 
-	sugar := logger.Sugar()
-	return &prettyLogger{sugar}, nil
+	return &zapLogger{logger}, nil
 }
@@ -0,0 +1,7 @@
+//go:build race
+
+package xlog_test
+
+// raceEnabled reports whether the race detector, which allocates on its
+// own, is enabled.
+const raceEnabled = true
@@ -0,0 +1,120 @@
+package xlog
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SamplingConfig configures a Sampler. Within each interval, the first
+// First messages with a given level and title are logged, then every
+// Thereafter-th one. A zero Thereafter drops the rest, and a zero Interval
+// never resets the counts.
+type SamplingConfig struct {
+	First      int
+	Thereafter int
+	Interval   time.Duration
+}
+
+type sampleKey struct {
+	level Level
+	title string
+}
+
+// Sampler is a Log that limits how often messages with the same level and
+// title are forwarded, to keep repeated failures from flooding the logs.
+// Titles should therefore be constant, with values in fields.
+type Sampler struct {
+	next Log
+	cfg  SamplingConfig
+
+	mu      sync.Mutex
+	counts  map[sampleKey]int
+	resetAt time.Time
+	dropped atomic.Uint64
+}
+
+// NewSampler returns a Sampler forwarding to next. Wrap a component's logger
+// to sample all of its messages, or create one for a single call site.
+func NewSampler(next Log, cfg SamplingConfig) *Sampler {
+	return &Sampler{
+		next:    next,
+		cfg:     cfg,
+		mu:      sync.Mutex{},
+		counts:  map[sampleKey]int{},
+		resetAt: time.Now().Add(cfg.Interval),
+		dropped: atomic.Uint64{},
+	}
+}
+
+// Dropped returns the number of messages dropped so far.
+func (s *Sampler) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Sync syncs the next logger if it is a Syncer.
+func (s *Sampler) Sync() error {
+	return syncLog(s.next)
+}
+
+// Close closes the next logger if it is a Closer, and syncs it otherwise.
+func (s *Sampler) Close() error {
+	return closeLog(s.next)
+}
+
+func (s *Sampler) unwrap() Log {
+	return s.next
+}
+
+func (s *Sampler) enabled(l Level) bool {
+	return logEnabled(s.next, l)
+}
+
+// sample reports whether the message should be logged, counting it as
+// dropped otherwise.
+func (s *Sampler) sample(l Level, msg Message) bool {
+	s.mu.Lock()
+	if now := time.Now(); s.cfg.Interval > 0 && !now.Before(s.resetAt) {
+		s.counts = map[sampleKey]int{}
+		s.resetAt = now.Add(s.cfg.Interval)
+	}
+
+	key := sampleKey{level: l, title: msg.Title}
+	s.counts[key]++
+	n := s.counts[key]
+	s.mu.Unlock()
+
+	if n <= s.cfg.First || (s.cfg.Thereafter > 0 && (n-s.cfg.First)%s.cfg.Thereafter == 0) {
+		return true
+	}
+	s.dropped.Add(1)
+	return false
+}
+
+// Info logs an info message if it is sampled.
+func (s *Sampler) Info(msg Message) {
+	if s.sample(InfoLevel, msg) {
+		s.next.Info(msg)
+	}
+}
+
+// Warn logs a warning message if it is sampled.
+func (s *Sampler) Warn(msg Message) {
+	if s.sample(WarnLevel, msg) {
+		s.next.Warn(msg)
+	}
+}
+
+// Error logs an error message if it is sampled.
+func (s *Sampler) Error(msg Message) {
+	if s.sample(ErrorLevel, msg) {
+		s.next.Error(msg)
+	}
+}
+
+// Debug logs a debug message if it is sampled.
+func (s *Sampler) Debug(msg Message) {
+	if s.sample(DebugLevel, msg) {
+		s.next.Debug(msg)
+	}
+}
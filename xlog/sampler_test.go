@@ -0,0 +1,75 @@
+package xlog_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hardiksachan/x/xlog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSampler(t *testing.T) {
+	t.Run("first messages are logged, then every nth", func(t *testing.T) {
+		o := xlog.NewObserver()
+		s := xlog.NewSampler(o, xlog.SamplingConfig{First: 2, Thereafter: 3, Interval: 0})
+
+		for i := 1; i <= 10; i++ {
+			s.Info(xlog.Messagef("poll failed").With(xlog.Int("i", i)))
+		}
+
+		var logged []interface{}
+		for _, e := range o.Entries() {
+			v, _ := e.Value("i")
+			logged = append(logged, v)
+		}
+		require.Equal(t, []interface{}{int64(1), int64(2), int64(5), int64(8)}, logged)
+		require.Equal(t, uint64(6), s.Dropped())
+	})
+
+	t.Run("messages are sampled by level and title", func(t *testing.T) {
+		o := xlog.NewObserver()
+		s := xlog.NewSampler(o, xlog.SamplingConfig{First: 1, Thereafter: 0, Interval: 0})
+
+		s.Info(xlog.Messagef("a"))
+		s.Info(xlog.Messagef("a"))
+		s.Warn(xlog.Messagef("a"))
+		s.Info(xlog.Messagef("b"))
+
+		require.Len(t, o.Entries(), 3)
+		require.Equal(t, uint64(1), s.Dropped())
+	})
+
+	t.Run("counts are reset every interval", func(t *testing.T) {
+		o := xlog.NewObserver()
+		s := xlog.NewSampler(o, xlog.SamplingConfig{First: 1, Thereafter: 0, Interval: 50 * time.Millisecond})
+
+		s.Error(xlog.Messagef("down"))
+		s.Error(xlog.Messagef("down"))
+		time.Sleep(60 * time.Millisecond)
+		s.Error(xlog.Messagef("down"))
+
+		require.Len(t, o.Entries(), 2)
+		require.Equal(t, uint64(1), s.Dropped())
+	})
+
+	t.Run("sampling is safe for concurrent use", func(t *testing.T) {
+		o := xlog.NewObserver()
+		s := xlog.NewSampler(o, xlog.SamplingConfig{First: 10, Thereafter: 10, Interval: time.Minute})
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 10; j++ {
+					s.Debug(xlog.Messagef("busy"))
+				}
+			}()
+		}
+		wg.Wait()
+
+		require.Len(t, o.Entries(), 19)
+		require.Equal(t, uint64(81), s.Dropped())
+	})
+}
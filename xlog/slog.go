@@ -0,0 +1,199 @@
+package xlog
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// detailsKey is the attribute holding Message.Details in slog records.
+const detailsKey = "details"
+
+// fromSlogLevel maps a slog level to the closest xlog level.
+func fromSlogLevel(l slog.Level) Level {
+	switch {
+	case l < slog.LevelInfo:
+		return DebugLevel
+	case l < slog.LevelWarn:
+		return InfoLevel
+	case l < slog.LevelError:
+		return WarnLevel
+	}
+	return ErrorLevel
+}
+
+// slogLevel maps an xlog level to the slog level.
+func (l Level) slogLevel() slog.Level {
+	switch l {
+	case DebugLevel:
+		return slog.LevelDebug
+	case InfoLevel:
+		return slog.LevelInfo
+	case WarnLevel:
+		return slog.LevelWarn
+	case ErrorLevel:
+		return slog.LevelError
+	}
+	return slog.LevelInfo
+}
+
+type slogHandler struct {
+	log    Log
+	prefix string
+	attrs  []slog.Attr
+}
+
+// NewSlogHandler returns a slog.Handler writing to l, e.g. xlog.Current(),
+// so slog and xlog share a sink. The record message becomes the title, a
+// top-level "details" string attribute the details, other string attributes
+// data and the remaining attributes typed fields. Groups are flattened into
+// dot-separated keys. Records below the level of l are dropped, and the data
+// and trace of the context are added as with InfoCtx.
+func NewSlogHandler(l Log) slog.Handler {
+	return &slogHandler{log: l, prefix: "", attrs: nil}
+}
+
+func (h *slogHandler) Enabled(_ context.Context, l slog.Level) bool {
+	return logEnabled(h.log, fromSlogLevel(l))
+}
+
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	msg := Message{
+		Title:   r.Message,
+		Details: "",
+		Data:    nil,
+		Fields:  nil,
+	}
+	for _, a := range h.attrs {
+		addSlogAttr(&msg, a)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		for _, flat := range flattenAttr(h.prefix, a) {
+			addSlogAttr(&msg, flat)
+		}
+		return true
+	})
+	LogAt(h.log, fromSlogLevel(r.Level), contextMessage(ctx, msg))
+	return nil
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	flat := make([]slog.Attr, 0, len(h.attrs)+len(attrs))
+	flat = append(flat, h.attrs...)
+	for _, a := range attrs {
+		flat = append(flat, flattenAttr(h.prefix, a)...)
+	}
+	return &slogHandler{log: h.log, prefix: h.prefix, attrs: flat}
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &slogHandler{log: h.log, prefix: h.prefix + name + ".", attrs: h.attrs}
+}
+
+// flattenAttr resolves a and returns it, or the attributes of its group,
+// with keys prefixed. Empty attributes and groups are dropped.
+func flattenAttr(prefix string, a slog.Attr) []slog.Attr {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return nil
+	}
+	if a.Value.Kind() != slog.KindGroup {
+		return []slog.Attr{{Key: prefix + a.Key, Value: a.Value}}
+	}
+
+	if a.Key != "" {
+		prefix += a.Key + "."
+	}
+	var flat []slog.Attr
+	for _, member := range a.Value.Group() {
+		flat = append(flat, flattenAttr(prefix, member)...)
+	}
+	return flat
+}
+
+// addSlogAttr adds a flattened attribute to msg.
+func addSlogAttr(msg *Message, a slog.Attr) {
+	switch a.Value.Kind() {
+	case slog.KindString:
+		if a.Key == detailsKey {
+			msg.Details = a.Value.String()
+			return
+		}
+		if msg.Data == nil {
+			msg.Data = map[string]string{}
+		}
+		msg.Data[a.Key] = a.Value.String()
+	case slog.KindInt64:
+		msg.Fields = append(msg.Fields, Int64(a.Key, a.Value.Int64()))
+	case slog.KindBool:
+		msg.Fields = append(msg.Fields, Bool(a.Key, a.Value.Bool()))
+	case slog.KindDuration:
+		msg.Fields = append(msg.Fields, Duration(a.Key, a.Value.Duration()))
+	case slog.KindTime:
+		msg.Fields = append(msg.Fields, Time(a.Key, a.Value.Time()))
+	case slog.KindAny:
+		if err, ok := a.Value.Any().(error); ok {
+			msg.Fields = append(msg.Fields, NamedErr(a.Key, err))
+			return
+		}
+		msg.Fields = append(msg.Fields, Any(a.Key, a.Value.Any()))
+	case slog.KindUint64, slog.KindFloat64, slog.KindGroup, slog.KindLogValuer:
+		msg.Fields = append(msg.Fields, Any(a.Key, a.Value.Any()))
+	}
+}
+
+type slogLog struct {
+	handler slog.Handler
+}
+
+// NewSlogLog returns a Log writing to h. The title becomes the record
+// message, the details a "details" attribute, data string attributes and
+// fields attributes of the matching kind.
+func NewSlogLog(h slog.Handler) Log {
+	return slogLog{handler: h}
+}
+
+func (s slogLog) Info(msg Message) {
+	s.write(InfoLevel, msg)
+}
+
+func (s slogLog) Warn(msg Message) {
+	s.write(WarnLevel, msg)
+}
+
+func (s slogLog) Error(msg Message) {
+	s.write(ErrorLevel, msg)
+}
+
+func (s slogLog) Debug(msg Message) {
+	s.write(DebugLevel, msg)
+}
+
+func (s slogLog) write(l Level, msg Message) {
+	ctx := context.Background()
+	if !s.handler.Enabled(ctx, l.slogLevel()) {
+		return
+	}
+
+	pc, ok := messageCaller(msg)
+	if !ok {
+		pc = callerPC()
+	}
+
+	r := slog.NewRecord(time.Now(), l.slogLevel(), msg.Title, pc)
+	if msg.Details != "" {
+		r.AddAttrs(slog.String(detailsKey, msg.Details))
+	}
+	for k, v := range msg.Data {
+		r.AddAttrs(slog.String(k, v))
+	}
+	for _, f := range msg.Fields {
+		if f.typ != skipField && f.typ != callerField {
+			r.AddAttrs(slog.Any(f.Key, f.Value()))
+		}
+	}
+	_ = s.handler.Handle(ctx, r)
+}
@@ -0,0 +1,141 @@
+package xlog_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/hardiksachan/x/xlog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlogHandler(t *testing.T) {
+	t.Run("records are mapped to messages", func(t *testing.T) {
+		o := xlog.NewObserver()
+		logger := slog.New(xlog.NewSlogHandler(o)).With("service", "orders")
+
+		logger.Warn("payment failed",
+			"details", "card declined",
+			"order_id", "42",
+			"amount", 1999,
+			"took", time.Second,
+			"err", errors.New("declined"),
+			slog.Group("user", "id", "u1", slog.Group("plan", "tier", "pro")),
+			slog.Group("empty"),
+		)
+
+		entries := o.Entries()
+		require.Len(t, entries, 1)
+		e := entries[0]
+
+		require.Equal(t, xlog.WarnLevel, e.Level)
+		require.Equal(t, "payment failed", e.Title)
+		require.Equal(t, "card declined", e.Details)
+		require.Equal(t, map[string]string{
+			"service":        "orders",
+			"order_id":       "42",
+			"user.id":        "u1",
+			"user.plan.tier": "pro",
+		}, e.Data)
+
+		amount, _ := e.Value("amount")
+		require.Equal(t, int64(1999), amount)
+		took, _ := e.Value("took")
+		require.Equal(t, time.Second, took)
+		require.Len(t, entries.WithField("err", "declined"), 1)
+	})
+
+	t.Run("groups prefix later attributes", func(t *testing.T) {
+		o := xlog.NewObserver()
+		logger := slog.New(xlog.NewSlogHandler(o)).With("a", "1").WithGroup("req").With("id", "r1")
+
+		logger.Info("handled", "status", "ok")
+
+		require.Equal(t, map[string]string{
+			"a":          "1",
+			"req.id":     "r1",
+			"req.status": "ok",
+		}, o.Entries()[0].Data)
+	})
+
+	t.Run("levels are mapped and filtered", func(t *testing.T) {
+		resetLevels(t)
+		xlog.SetLevel(xlog.InfoLevel)
+
+		o := xlog.NewObserver()
+		logger := slog.New(xlog.NewSlogHandler(xlog.WithName(o, "")))
+
+		logger.Debug("dropped")
+		logger.Info("info")
+		logger.Log(context.Background(), slog.LevelError+4, "fatal")
+
+		entries := o.Entries()
+		require.Equal(t, []string{"info", "fatal"}, entries.Titles())
+		require.Equal(t, xlog.ErrorLevel, entries[1].Level)
+	})
+
+	t.Run("the level of the wrapped logger applies", func(t *testing.T) {
+		resetLevels(t, "outbox")
+		xlog.SetLevel(xlog.InfoLevel)
+		xlog.SetLevelFor("outbox", xlog.DebugLevel)
+
+		o := xlog.NewObserver()
+		slog.New(xlog.NewSlogHandler(xlog.WithName(o, "outbox"))).Debug("outbox")
+		slog.New(xlog.NewSlogHandler(xlog.WithName(o, "inbox"))).Debug("inbox")
+
+		require.Equal(t, []string{"outbox"}, o.Entries().Titles())
+	})
+
+	t.Run("context data is added", func(t *testing.T) {
+		o := xlog.NewObserver()
+		logger := slog.New(xlog.NewSlogHandler(o))
+
+		ctx := xlog.ContextWithTrace(context.Background(), "t1", "s1")
+		logger.InfoContext(ctx, "traced")
+
+		require.Equal(t, "t1", o.Entries()[0].Data[xlog.TraceIDKey])
+	})
+
+	t.Run("caller is the slog call site", func(t *testing.T) {
+		var buf bytes.Buffer
+		json, err := xlog.New(xlog.Config{Format: xlog.FormatJSON, Output: &buf})
+		require.NoError(t, err)
+
+		slog.New(xlog.NewSlogHandler(json)).Info("hello")
+
+		entries := decodeEntries(t, &buf)
+		require.Len(t, entries, 1)
+		require.Contains(t, entries[0]["caller"], "xlog/slog_test.go")
+	})
+}
+
+func TestSlogLog(t *testing.T) {
+	var buf bytes.Buffer
+	handler := slog.NewJSONHandler(&buf, &slog.HandlerOptions{AddSource: true, Level: slog.LevelInfo, ReplaceAttr: nil})
+	logger := xlog.NewSlogLog(handler)
+
+	logger.Debug(xlog.Messagef("dropped"))
+	logger.Error(xlog.Message{
+		Title:   "payment failed",
+		Details: "card declined",
+		Data:    map[string]string{"order_id": "42"},
+		Fields:  []xlog.Field{xlog.Int("amount", 1999), xlog.Err(errors.New("declined")), xlog.Err(nil)},
+	})
+
+	entries := decodeEntries(t, &buf)
+	require.Len(t, entries, 1)
+	entry := entries[0]
+
+	require.Equal(t, "ERROR", entry["level"])
+	require.Equal(t, "payment failed", entry["msg"])
+	require.Equal(t, "card declined", entry["details"])
+	require.Equal(t, "42", entry["order_id"])
+	require.Equal(t, float64(1999), entry["amount"])
+	require.Equal(t, "declined", entry["error"])
+
+	source, _ := entry["source"].(map[string]interface{})
+	require.Contains(t, source["file"], "xlog/slog_test.go")
+}
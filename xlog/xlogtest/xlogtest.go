@@ -0,0 +1,55 @@
+// Package xlogtest provides helpers for asserting on logs in tests. It is
+// kept out of xlog so that programs do not link the testing package.
+package xlogtest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hardiksachan/x/xlog"
+)
+
+// Observe installs a new Observer as the default logger and restores the
+// previous default when the test finishes.
+func Observe(t testing.TB) *xlog.Observer {
+	t.Helper()
+
+	o := xlog.NewObserver()
+	prev := xlog.SwapDefault(o)
+	t.Cleanup(func() { xlog.SetDefault(prev) })
+	return o
+}
+
+// AssertLogged fails the test unless o recorded an entry at the given level
+// containing s, and returns whether it did.
+func AssertLogged(t testing.TB, o *xlog.Observer, l xlog.Level, s string) bool {
+	t.Helper()
+
+	if len(o.Entries().ByLevel(l).Containing(s)) > 0 {
+		return true
+	}
+	t.Errorf("xlogtest: no %s entry containing %q was logged%s", l, s, dump(o))
+	return false
+}
+
+// AssertNotLogged fails the test if o recorded an entry at the given level
+// containing s, and returns whether it did not.
+func AssertNotLogged(t testing.TB, o *xlog.Observer, l xlog.Level, s string) bool {
+	t.Helper()
+
+	if len(o.Entries().ByLevel(l).Containing(s)) == 0 {
+		return true
+	}
+	t.Errorf("xlogtest: unexpected %s entry containing %q was logged%s", l, s, dump(o))
+	return false
+}
+
+func dump(o *xlog.Observer) string {
+	var b strings.Builder
+	b.WriteString(", entries:")
+	for _, e := range o.Entries() {
+		b.WriteString("\n\t")
+		b.WriteString(e.String())
+	}
+	return b.String()
+}
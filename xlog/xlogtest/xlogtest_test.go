@@ -0,0 +1,52 @@
+package xlogtest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hardiksachan/x/xlog"
+	"github.com/hardiksachan/x/xlog/xlogtest"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingT struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recordingT) Errorf(format string, _ ...interface{}) {
+	r.failures = append(r.failures, format)
+}
+
+func TestObserve(t *testing.T) {
+	t.Run("Observe captures the default logger until the test ends", func(t *testing.T) {
+		var inner *xlog.Observer
+		t.Run("observed", func(t *testing.T) {
+			inner = xlogtest.Observe(t)
+
+			xlog.InfoCtx(xlog.WithData(context.Background(), map[string]string{"request_id": "r1"}), xlog.Messagef("hello"))
+			xlog.Named("outbox").Debug(xlog.Messagef("polled"))
+
+			require.True(t, xlogtest.AssertLogged(t, inner, xlog.InfoLevel, "hello"))
+			require.True(t, xlogtest.AssertLogged(t, inner, xlog.DebugLevel, "polled"))
+			require.True(t, xlogtest.AssertNotLogged(t, inner, xlog.ErrorLevel, "hello"))
+			require.Len(t, inner.Entries().WithField("request_id", "r1"), 1)
+		})
+
+		outer := xlogtest.Observe(t)
+		xlog.InfoString("after")
+
+		require.Len(t, inner.Entries(), 2)
+		require.Equal(t, []string{"after"}, outer.Entries().Titles())
+	})
+
+	t.Run("assertions report the recorded entries", func(t *testing.T) {
+		o := xlog.NewObserver()
+		o.Info(xlog.Messagef("hello"))
+
+		r := &recordingT{TB: t, failures: nil}
+		require.False(t, xlogtest.AssertLogged(r, o, xlog.ErrorLevel, "hello"))
+		require.False(t, xlogtest.AssertNotLogged(r, o, xlog.InfoLevel, "hello"))
+		require.Len(t, r.failures, 2)
+	})
+}
@@ -0,0 +1,200 @@
+package xlog
+
+import (
+	"errors"
+	"runtime"
+	"strings"
+	"sync"
+	"syscall"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Prefixes of the functions skipped when looking for the caller: this
+// package, xerrors when it logs the errors it translates, and slog when
+// logging through NewSlogHandler.
+const (
+	packagePrefix = "github.com/hardiksachan/x/xlog."
+	xerrorsPrefix = "github.com/hardiksachan/x/xerrors."
+	slogPrefix    = "log/slog."
+)
+
+const (
+	// callerDepth is the number of frames searched for the caller.
+	callerDepth = 16
+	// pooledFields is the initial capacity of pooled field slices.
+	pooledFields = 8
+)
+
+// zapLogger writes messages to a zap logger. The title is the entry
+// message, and details, data and fields are structured fields.
+type zapLogger struct {
+	logger *zap.Logger
+}
+
+func (z *zapLogger) Info(msg Message) {
+	z.write(zapcore.InfoLevel, msg)
+}
+
+func (z *zapLogger) Warn(msg Message) {
+	z.write(zapcore.WarnLevel, msg)
+}
+
+func (z *zapLogger) Error(msg Message) {
+	z.write(zapcore.ErrorLevel, msg)
+}
+
+func (z *zapLogger) Debug(msg Message) {
+	z.write(zapcore.DebugLevel, msg)
+}
+
+func (z *zapLogger) write(level zapcore.Level, msg Message) {
+	ce := z.logger.Check(level, msg.Title)
+	if ce == nil {
+		return
+	}
+	if pc, ok := messageCaller(msg); ok {
+		ce.Caller = resolveCaller(pc).caller
+	} else {
+		ce.Caller = caller()
+	}
+
+	fields, _ := fieldsPool.Get().(*[]zap.Field)
+	*fields = appendZapFields((*fields)[:0], msg)
+	ce.Write(*fields...)
+	clear(*fields)
+	fieldsPool.Put(fields)
+}
+
+// Sync flushes the output. Errors from syncing terminals and pipes, which
+// do not support it, are ignored.
+func (z *zapLogger) Sync() error {
+	err := z.logger.Sync()
+	if errors.Is(err, syscall.EINVAL) || errors.Is(err, syscall.ENOTTY) || errors.Is(err, syscall.EBADF) {
+		return nil
+	}
+	return err
+}
+
+// fieldsPool holds the slices messages are converted into, so that
+// logging does not allocate them.
+var fieldsPool = sync.Pool{
+	New: func() interface{} {
+		fields := make([]zap.Field, 0, pooledFields)
+		return &fields
+	},
+}
+
+// appendZapFields appends the details, data and fields of the message to
+// fields as zap fields. Data and fields with reserved keys are prefixed.
+func appendZapFields(fields []zap.Field, msg Message) []zap.Field {
+	if msg.Details != "" {
+		fields = append(fields, zap.String(detailsKey, msg.Details))
+	}
+	for k, v := range msg.Data {
+		fields = append(fields, zap.String(entryKey(k), v))
+	}
+	for _, f := range msg.Fields {
+		if f.typ != callerField {
+			f.Key = entryKey(f.Key)
+			fields = append(fields, zapField(f))
+		}
+	}
+	return fields
+}
+
+// callerEntry is the resolved call site of a program counter.
+type callerEntry struct {
+	caller zapcore.EntryCaller
+	// trimmed is the trimmed path of the caller, as encoded in messages.
+	trimmed string
+	// skip reports whether every frame of the program counter is in a
+	// skipped package.
+	skip bool
+}
+
+// callers caches the resolved program counters, so that finding and
+// encoding the caller does not allocate. It is bounded by the number of
+// call sites in the program.
+var callers = struct {
+	sync.RWMutex
+	entries map[uintptr]callerEntry
+}{
+	RWMutex: sync.RWMutex{},
+	entries: make(map[uintptr]callerEntry),
+}
+
+// resolveCaller returns the innermost frame of pc outside the skipped
+// packages, resolving it on first use.
+func resolveCaller(pc uintptr) callerEntry {
+	callers.RLock()
+	e, ok := callers.entries[pc]
+	callers.RUnlock()
+	if ok {
+		return e
+	}
+
+	e = callerEntry{caller: zapcore.NewEntryCaller(0, "", 0, false), trimmed: "", skip: true}
+	frames := runtime.CallersFrames([]uintptr{pc})
+	for {
+		frame, more := frames.Next()
+		if !skipCaller(frame.Function) {
+			c := zapcore.NewEntryCaller(pc, frame.File, frame.Line, true)
+			e = callerEntry{caller: c, trimmed: c.TrimmedPath(), skip: false}
+			break
+		}
+		if !more {
+			break
+		}
+	}
+
+	callers.Lock()
+	callers.entries[pc] = e
+	callers.Unlock()
+	return e
+}
+
+// encodeCaller is zapcore.ShortCallerEncoder, reusing the path trimmed when
+// the caller was resolved.
+func encodeCaller(c zapcore.EntryCaller, enc zapcore.PrimitiveArrayEncoder) {
+	callers.RLock()
+	e, ok := callers.entries[c.PC]
+	callers.RUnlock()
+	if ok && e.caller == c {
+		enc.AppendString(e.trimmed)
+		return
+	}
+	zapcore.ShortCallerEncoder(c, enc)
+}
+
+// caller returns the first caller outside the skipped packages.
+func caller() zapcore.EntryCaller {
+	var pcs [callerDepth]uintptr
+	n := runtime.Callers(2, pcs[:]) //nolint:gomnd
+	for _, pc := range pcs[:n] {
+		if e := resolveCaller(pc); !e.skip {
+			return e.caller
+		}
+	}
+	return zapcore.NewEntryCaller(0, "", 0, false)
+}
+
+// callerPC returns the program counter of the first caller outside the
+// skipped packages, for handlers that resolve the source themselves.
+func callerPC() uintptr {
+	var pcs [callerDepth]uintptr
+	n := runtime.Callers(2, pcs[:]) //nolint:gomnd
+	for _, pc := range pcs[:n] {
+		if !resolveCaller(pc).skip {
+			return pc
+		}
+	}
+	return 0
+}
+
+func skipCaller(function string) bool {
+	return strings.HasPrefix(function, packagePrefix) ||
+		strings.HasPrefix(function, xerrorsPrefix) ||
+		strings.HasPrefix(function, slogPrefix)
+}
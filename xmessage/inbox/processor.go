@@ -16,8 +16,18 @@ const (
 	defaultMaxLockAge      = 120 * time.Second
 	defaultMaxRetries      = 3
 	defaultRetryInterval   = 30 * time.Second
+
+	loggerName = "inbox"
 )
 
+// defaultLogSampling limits the errors logged by clearing locks, which fails
+// on every iteration while the database is unavailable.
+var defaultLogSampling = xlog.SamplingConfig{
+	First:      3,
+	Thereafter: 30,
+	Interval:   time.Minute,
+}
+
 // HandleFunc is a function that handles a message
 type HandleFunc func(ctx context.Context, message *xmessage.Message) error
 
@@ -33,6 +43,8 @@ type Processor struct {
 	maxLockAge      time.Duration
 	maxRetries      int
 	retryInterval   time.Duration
+	log             xlog.Log
+	logSampling     xlog.SamplingConfig
 }
 
 // ProcessorOption is a function that configures a Processor
@@ -61,6 +73,25 @@ func WithRetries(maxRetries int, retryInterval time.Duration) ProcessorOption {
 	}
 }
 
+// WithLogger sets the logger, which defaults to xlog.Named("inbox"). A nil
+// logger discards messages, as with xlog.SetDefault
+func WithLogger(l xlog.Log) ProcessorOption {
+	return func(i *Processor) {
+		if l == nil {
+			l = xlog.Nop()
+		}
+		i.log = l
+	}
+}
+
+// WithLogSampling sets how often clearing locks logs repeated errors. Use a
+// Thereafter of 1 to log every error.
+func WithLogSampling(cfg xlog.SamplingConfig) ProcessorOption {
+	return func(i *Processor) {
+		i.logSampling = cfg
+	}
+}
+
 // NewProcessor creates a new inbox.Processor
 func NewProcessor(r Repository, types []string, handler HandleFunc, opts ...ProcessorOption) Processor {
 	i := Processor{
@@ -74,6 +105,8 @@ func NewProcessor(r Repository, types []string, handler HandleFunc, opts ...Proc
 		maxLockAge:      defaultMaxLockAge,
 		maxRetries:      defaultMaxRetries,
 		retryInterval:   defaultRetryInterval,
+		log:             xlog.Named(loggerName),
+		logSampling:     defaultLogSampling,
 	}
 
 	for _, opt := range opts {
@@ -98,9 +131,10 @@ func (p *Processor) processMessages(ctx context.Context) {
 			continue
 		}
 
-		err = p.handle(ctx, message)
+		msgCtx := xmessage.LogContext(ctx, message)
+		err = p.handle(msgCtx, message)
 		if err != nil {
-			xlog.Infof("error handling message %s: %+v", message.Type, err)
+			xlog.WithContext(p.log, msgCtx).Info(xlog.Messagef("error handling message %s", message.Type).With(xlog.Err(err)))
 			_ = p.r.MarkForRetry(ctx, message.ID, time.Now().Add(p.retryInterval))
 			continue
 		}
@@ -111,13 +145,14 @@ func (p *Processor) processMessages(ctx context.Context) {
 
 func (p *Processor) clearLocks(ctx context.Context) {
 	op := xerrors.Op("inbox.Processor.clearLocks")
+	errLog := xlog.NewSampler(p.log, p.logSampling)
 
 	for {
 		time.Sleep(p.lockingInterval)
 
 		err := p.r.ClearLocks(ctx, p.id, time.Now().Add(-p.maxLockAge))
 		if err != nil {
-			xlog.Infof("%s, error clearing locks: %+v", op, err)
+			errLog.Info(xlog.Messagef("%s: error clearing locks", op).With(xlog.Err(err)))
 		}
 	}
 }
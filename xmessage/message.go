@@ -3,6 +3,12 @@
 // and idempotent consumer.
 package xmessage
 
+import (
+	"context"
+
+	"github.com/hardiksachan/x/xlog"
+)
+
 // Topic is a topic of events.
 type Topic string
 
@@ -30,3 +36,17 @@ type Delivery interface {
 	Ack() error
 	Nack() error
 }
+
+// Keys of the log data attached to the context a message is handled with.
+const (
+	MessageIDKey   = "message_id"
+	MessageTypeKey = "message_type"
+)
+
+// LogContext returns a copy of ctx carrying the ID and type of m as log data.
+func LogContext(ctx context.Context, m *Message) context.Context {
+	return xlog.WithData(ctx, map[string]string{
+		MessageIDKey:   m.ID,
+		MessageTypeKey: m.Type,
+	})
+}
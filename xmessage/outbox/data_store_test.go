@@ -54,6 +54,9 @@ func (ds *testDataStore) isProcessed(id string) bool {
 }
 
 func (ds *testDataStore) AddPublishing(p *xmessage.Publishing) {
+	ds.Lock()
+	defer ds.Unlock()
+
 	ds.publishings[p.Message.ID] = &publishingWithStatus{
 		publishing: p,
 		processed:  false,
@@ -5,12 +5,15 @@ import (
 	"context"
 
 	"github.com/hardiksachan/x/xerrors"
+	"github.com/hardiksachan/x/xlog"
 	"github.com/hardiksachan/x/xmessage"
 	"github.com/hardiksachan/x/xretry"
 )
 
 const (
 	failedMessagesChanSize = 10
+
+	loggerName = "outbox"
 )
 
 // DataStore is the interface that wraps the Read method
@@ -32,20 +35,40 @@ type FailedPublishing struct {
 
 // Outbox is a transactional outbox
 type Outbox struct {
-	ds DataStore
-	ps PublishingStream
-	r  *xretry.Retrier
-	fm chan *FailedPublishing
+	ds  DataStore
+	ps  PublishingStream
+	r   *xretry.Retrier
+	fm  chan *FailedPublishing
+	log xlog.Log
+}
+
+// Option is used to configure the Outbox
+type Option func(*Outbox)
+
+// WithLogger sets the logger, which defaults to xlog.Named("outbox"). A nil
+// logger discards messages, as with xlog.SetDefault
+func WithLogger(l xlog.Log) Option {
+	return func(o *Outbox) {
+		if l == nil {
+			l = xlog.Nop()
+		}
+		o.log = l
+	}
 }
 
 // New creates a new Outbox
-func New(ds DataStore, p PublishingStream, r *xretry.Retrier) Outbox {
-	return Outbox{
-		ds: ds,
-		ps: p,
-		r:  r,
-		fm: make(chan *FailedPublishing, failedMessagesChanSize),
+func New(ds DataStore, p PublishingStream, r *xretry.Retrier, opts ...Option) Outbox {
+	o := Outbox{
+		ds:  ds,
+		ps:  p,
+		r:   r,
+		fm:  make(chan *FailedPublishing, failedMessagesChanSize),
+		log: xlog.Named(loggerName),
+	}
+	for _, opt := range opts {
+		opt(&o)
 	}
+	return o
 }
 
 // Start will start the outbox
@@ -68,14 +91,16 @@ func (o *Outbox) startDispatcher(ctx context.Context, publishings <-chan *xmessa
 	for {
 		select {
 		case p := <-publishings:
+			msgCtx := xmessage.LogContext(ctx, p.Message)
 			err := o.r.Retry((func() error {
-				return o.ps.Send(ctx, p)
+				return o.ps.Send(msgCtx, p)
 			}))
 			if err != nil {
 				fm := &FailedPublishing{
 					Publishing: p,
 					Err:        xerrors.E(op, err),
 				}
+				xlog.WithContext(o.log, msgCtx).Warn(xlog.Messagef("%s: failed to dispatch message", op).With(xlog.Err(fm.Err)))
 
 				o.fm <- fm
 			}
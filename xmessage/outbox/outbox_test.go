@@ -62,6 +62,19 @@ func TestStart(t *testing.T) {
 		}, time.Second, time.Millisecond*100)
 	})
 
+	t.Run("publishings are sent with the message in the log context", func(t *testing.T) {
+		p := newPublishing()
+		ds.AddPublishing(p)
+
+		require.Eventually(t, func() bool {
+			return es.loggedData(p.Message.ID) != nil
+		}, time.Second, time.Millisecond*100)
+
+		data := es.loggedData(p.Message.ID)
+		require.Equal(t, p.Message.ID, data[xmessage.MessageIDKey])
+		require.Equal(t, p.Message.Type, data[xmessage.MessageTypeKey])
+	})
+
 	t.Run("when publishings are sent to event stream, they are marked as processed", func(t *testing.T) {
 		p := newPublishing()
 		ds.AddPublishing(p)
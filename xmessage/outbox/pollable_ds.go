@@ -18,6 +18,14 @@ const (
 	defaultRetryInterval   = 30 * time.Second
 )
 
+// defaultLogSampling limits the errors logged by the polling loops, which
+// fail on every iteration while the database is unavailable.
+var defaultLogSampling = xlog.SamplingConfig{
+	First:      3,
+	Thereafter: 30,
+	Interval:   time.Minute,
+}
+
 // PollableRepository is the interface that will need to be implemented by the consumer
 type PollableRepository interface {
 	GetUnsentPublishing(ctx context.Context, instanceID string, maxRetries int) (*xmessage.Publishing, error)
@@ -33,6 +41,8 @@ type PollingPolicy struct {
 	maxLockAge      time.Duration
 	maxRetries      int
 	retryInterval   time.Duration
+	log             xlog.Log
+	logSampling     xlog.SamplingConfig
 }
 
 // PollingOption is used to configure the polling interval
@@ -61,6 +71,25 @@ func WithLock(interval time.Duration, age time.Duration) PollingOption {
 	}
 }
 
+// WithPollingLogger sets the logger, which defaults to xlog.Named("outbox").
+// A nil logger discards messages, as with xlog.SetDefault
+func WithPollingLogger(l xlog.Log) PollingOption {
+	return func(p *PollingPolicy) {
+		if l == nil {
+			l = xlog.Nop()
+		}
+		p.log = l
+	}
+}
+
+// WithLogSampling sets how often the polling loops log repeated errors. Use
+// a Thereafter of 1 to log every error.
+func WithLogSampling(cfg xlog.SamplingConfig) PollingOption {
+	return func(p *PollingPolicy) {
+		p.logSampling = cfg
+	}
+}
+
 // NewPollingPolicy creates a new PollingPolicy
 func NewPollingPolicy(opts ...PollingOption) *PollingPolicy {
 	p := PollingPolicy{
@@ -69,6 +98,8 @@ func NewPollingPolicy(opts ...PollingOption) *PollingPolicy {
 		maxLockAge:      defaultMaxLockAge,
 		maxRetries:      defaultMaxRetries,
 		retryInterval:   defaultRetryInterval,
+		log:             xlog.Named(loggerName),
+		logSampling:     defaultLogSampling,
 	}
 	for _, opt := range opts {
 		opt(&p)
@@ -94,6 +125,7 @@ func NewPollableDataSource(r PollableRepository, p *PollingPolicy) *PollableData
 
 func (p *PollableDataSource) startPolling(ctx context.Context, publishings chan<- *xmessage.Publishing) {
 	op := xerrors.Op("outbox.PollableDataSource.startPolling")
+	errLog := xlog.NewSampler(p.p.log, p.p.logSampling)
 
 	for {
 		time.Sleep(p.p.pollingInterval)
@@ -105,20 +137,21 @@ func (p *PollableDataSource) startPolling(ctx context.Context, publishings chan<
 		}
 
 		if xerrors.ErrorCode(err) != xerrors.NotFound {
-			xlog.Infof("%s: error getting unsent publishings: %+v", op, err)
+			errLog.Info(xlog.Messagef("%s: error getting unsent publishings", op).With(xlog.Err(err)))
 		}
 	}
 }
 
 func (p *PollableDataSource) clearLocks(ctx context.Context) {
 	op := xerrors.Op("outbox.PollableDataSource.clearLocks")
+	errLog := xlog.NewSampler(p.p.log, p.p.logSampling)
 
 	for {
 		time.Sleep(p.p.lockingInterval)
 
 		err := p.r.ClearLocks(ctx, p.instanceID, time.Now().Add(-p.p.maxLockAge))
 		if err != nil {
-			xlog.Infof("%s, error clearing locks: %+v", op, err)
+			errLog.Info(xlog.Messagef("%s: error clearing locks", op).With(xlog.Err(err)))
 		}
 	}
 }
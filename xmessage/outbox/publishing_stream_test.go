@@ -3,17 +3,22 @@ package outbox_test
 import (
 	"context"
 	"strings"
+	"sync"
 
 	"github.com/hardiksachan/x/xerrors"
+	"github.com/hardiksachan/x/xlog"
 	"github.com/hardiksachan/x/xmessage"
 	"github.com/hardiksachan/x/xtest"
 )
 
 type mockPublishingStream struct {
 	publishings map[string]*xmessage.Publishing
+
+	mu      sync.Mutex
+	logData map[string]map[string]string
 }
 
-func (ps *mockPublishingStream) Send(_ context.Context, p *xmessage.Publishing) error {
+func (ps *mockPublishingStream) Send(ctx context.Context, p *xmessage.Publishing) error {
 	if strings.HasPrefix(string(p.Topic), "fail:") {
 		return xerrors.E(xerrors.Message("failed to send message"))
 	}
@@ -26,6 +31,9 @@ func (ps *mockPublishingStream) Send(_ context.Context, p *xmessage.Publishing)
 	}
 
 	ps.publishings[p.Message.ID] = p
+	ps.mu.Lock()
+	ps.logData[p.Message.ID] = xlog.Data(ctx)
+	ps.mu.Unlock()
 	return nil
 }
 
@@ -34,8 +42,18 @@ func (ps *mockPublishingStream) isSent(id string) bool {
 	return ok
 }
 
+// loggedData returns the log data of the context the publishing was sent with.
+func (ps *mockPublishingStream) loggedData(id string) map[string]string {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	return ps.logData[id]
+}
+
 func newMockPublishingStream() *mockPublishingStream {
 	return &mockPublishingStream{
 		publishings: make(map[string]*xmessage.Publishing),
+		mu:          sync.Mutex{},
+		logData:     make(map[string]map[string]string),
 	}
 }
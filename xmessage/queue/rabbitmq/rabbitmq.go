@@ -18,25 +18,59 @@ type RabbitClient struct {
 	conn *amqp.Connection
 	// The channel that processes/sends Messages
 	ch *amqp.Channel
+	// The logger used by the client and the producers built on it
+	log xlog.Log
+}
+
+const loggerName = "rabbitmq"
+
+type options struct {
+	log xlog.Log
+}
+
+// Option is used to configure connections and clients
+type Option func(*options)
+
+// WithLogger sets the logger, which defaults to xlog.Named("rabbitmq"). A nil
+// logger discards messages, as with xlog.SetDefault
+func WithLogger(l xlog.Log) Option {
+	return func(o *options) {
+		if l == nil {
+			l = xlog.Nop()
+		}
+		o.log = l
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		log: xlog.Named(loggerName),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
 }
 
 // ConnectRabbitMQ will spawn a Connection
-func ConnectRabbitMQ(username, password, host, vhost string) (*amqp.Connection, error) {
+func ConnectRabbitMQ(username, password, host, vhost string, opts ...Option) (*amqp.Connection, error) {
 	op := xerrors.Op("queue.ConnectRabbitMQ")
+	o := newOptions(opts)
 
 	// Setup the Connection to RabbitMQ host using AMQPs
 	conn, err := amqp.Dial(fmt.Sprintf("amqp://%s:%s@%s/%s", username, password, host, vhost))
 	if err != nil {
 		return nil, xerrors.E(op, err)
 	}
-	xlog.DebugString("Connected to RabbitMQ")
+	o.log.Debug(xlog.Messagef("Connected to RabbitMQ"))
 	return conn, nil
 }
 
 // NewRabbitMQClient will connect and return a Rabbitclient with an open connection
 // Accepts a amqp Connection to be reused, to avoid spawning one TCP connection per concurrent client
-func NewRabbitMQClient(conn *amqp.Connection) (*RabbitClient, error) {
+func NewRabbitMQClient(conn *amqp.Connection, opts ...Option) (*RabbitClient, error) {
 	op := xerrors.Op("queue.NewRabbitMQClient")
+	o := newOptions(opts)
 
 	// Unique, Conncurrent Server Channel to process/send messages
 	// A good rule of thumb is to always REUSE Conn across applications
@@ -53,6 +87,7 @@ func NewRabbitMQClient(conn *amqp.Connection) (*RabbitClient, error) {
 	return &RabbitClient{
 		conn: conn,
 		ch:   ch,
+		log:  o.log,
 	}, nil
 }
 
@@ -28,7 +28,7 @@ func NewRabbitProducer(client *RabbitClient, exchange queue.Exchange) RabbitProd
 func (rp *RabbitProducer) Send(ctx context.Context, publishing *xmessage.Publishing) error {
 	op := xerrors.Op("queue.RabbitProducer.Send")
 
-	xlog.Debugf("sending message to exchange %s with topic %s: %+v", rp.exchange, publishing.Topic, publishing.Message)
+	xlog.WithContext(rp.client.log, ctx).Debug(xlog.Messagef("sending message to exchange %s with topic %s: %+v", rp.exchange, publishing.Topic, publishing.Message))
 
 	// TODO: make it traceable
 	//nolint:exhaustruct
@@ -2,17 +2,65 @@
 package xretry
 
 import (
+	"errors"
+	"math"
+	"math/rand"
 	"time"
 
 	"github.com/hardiksachan/x/xerrors"
+	"github.com/hardiksachan/x/xlog"
 )
 
+const (
+	// attemptsField is the error field recording the number of attempts.
+	attemptsField = "xretry.attempts"
+
+	loggerName = "xretry"
+)
+
+// Jitter is a strategy for randomizing the delays between retries with
+// backoff, so that clients failing together do not retry in lockstep.
+type Jitter int
+
+// Jitter strategies.
+const (
+	// NoJitter waits for the exact backoff delay.
+	NoJitter Jitter = iota
+	// FullJitter waits for a random delay between zero and the backoff delay.
+	FullJitter
+	// EqualJitter waits for half the backoff delay plus a random delay up to
+	// the other half.
+	EqualJitter
+	// DecorrelatedJitter waits for a random delay between the initial delay
+	// and the previous delay multiplied by the backoff factor.
+	DecorrelatedJitter
+)
+
+// RandSource is a source of random numbers for jitter. *rand.Rand
+// implements it.
+type RandSource interface {
+	// Int63n returns a non-negative random number less than n.
+	Int63n(n int64) int64
+}
+
+// globalRand is the RandSource backed by the math/rand package functions.
+type globalRand struct{}
+
+func (globalRand) Int63n(n int64) int64 {
+	return rand.Int63n(n) //nolint:gosec // jitter does not need a secure source
+}
+
 // RetryPolicy is the retry policy
 type RetryPolicy struct {
 	immediateRetries   int
 	retriesWithBackoff int
 	delay              time.Duration
 	backoffFactor      float64
+	jitter             Jitter
+	maxDelay           time.Duration
+	maxElapsedTime     time.Duration
+	rand               RandSource
+	log                xlog.Log
 }
 
 // RetryPolicyOption is the option for the retry policy
@@ -42,6 +90,51 @@ func WithNoRetries() RetryPolicyOption {
 	}
 }
 
+// WithJitter sets the jitter strategy applied to the delays between retries
+// with backoff. Defaults to NoJitter.
+func WithJitter(j Jitter) RetryPolicyOption {
+	return func(p *RetryPolicy) {
+		p.jitter = j
+	}
+}
+
+// WithMaxDelay caps the delay between retries with backoff. Zero, the
+// default, leaves it uncapped.
+func WithMaxDelay(d time.Duration) RetryPolicyOption {
+	return func(p *RetryPolicy) {
+		p.maxDelay = d
+	}
+}
+
+// WithMaxElapsedTime sets the time budget of Retry. No retry is started, and
+// no delay waited for, that would end after the budget runs out. Zero, the
+// default, leaves it unlimited.
+func WithMaxElapsedTime(d time.Duration) RetryPolicyOption {
+	return func(p *RetryPolicy) {
+		p.maxElapsedTime = d
+	}
+}
+
+// WithRandSource sets the source of random numbers for jitter, e.g. a seeded
+// *rand.Rand for deterministic tests. Defaults to the math/rand functions.
+func WithRandSource(r RandSource) RetryPolicyOption {
+	return func(p *RetryPolicy) {
+		p.rand = r
+	}
+}
+
+// WithLogger sets the logger for failed attempts, which defaults to
+// xlog.Named("xretry"). A nil logger discards messages, as with
+// xlog.SetDefault
+func WithLogger(l xlog.Log) RetryPolicyOption {
+	return func(p *RetryPolicy) {
+		if l == nil {
+			l = xlog.Nop()
+		}
+		p.log = l
+	}
+}
+
 // NewRetryPolicy creates a new RetryPolicy
 func NewRetryPolicy(opts ...RetryPolicyOption) RetryPolicy {
 	p := RetryPolicy{
@@ -49,6 +142,11 @@ func NewRetryPolicy(opts ...RetryPolicyOption) RetryPolicy {
 		retriesWithBackoff: 0,
 		delay:              0,
 		backoffFactor:      0,
+		jitter:             NoJitter,
+		maxDelay:           0,
+		maxElapsedTime:     0,
+		rand:               globalRand{},
+		log:                xlog.Named(loggerName),
 	}
 
 	for _, opt := range opts {
@@ -70,43 +168,179 @@ func NewRetrier(p RetryPolicy) *Retrier {
 	}
 }
 
-// Retry will retry the given function
+// Retry will retry the given function. Errors that xerrors.IsRetryable
+// classifies as permanent are returned immediately. The last error is
+// returned as is, without an extra op, and records the number of attempts,
+// which can be read with Attempts.
 func (r *Retrier) Retry(f func() error) error {
-	op := xerrors.Op("outbox.Retrier.Retry")
-	err := immediatelyRetry(f, r.p.immediateRetries)
-	if err != nil {
-		err = retryWithBackoff(f, r.p.retriesWithBackoff, r.p.delay, r.p.backoffFactor)
+	op := xerrors.Op("xretry.Retrier.Retry")
+
+	attempts := 0
+	attempt := func() error {
+		attempts++
+		err := f()
 		if err != nil {
-			return xerrors.E(op, err)
+			r.p.log.Debug(xlog.Messagef("%s: attempt failed", op).With(xlog.Int("attempt", attempts), xlog.Err(err)))
 		}
+		return err
+	}
+
+	b := r.p.newBackoff()
+	err := b.immediatelyRetry(attempt, r.p.immediateRetries)
+	if err != nil && xerrors.IsRetryable(err) {
+		err = b.retryWithBackoff(attempt, r.p.retriesWithBackoff)
+	}
+	if err != nil {
+		return withAttempts(err, attempts)
 	}
 
 	return nil
 }
 
-func immediatelyRetry(f func() error, retriesLeft int) error {
+// Attempts returns the number of attempts recorded on an error returned by
+// Retry, or 0 if there are none.
+func Attempts(err error) int {
+	var ae *attemptsError
+	if errors.As(err, &ae) {
+		return ae.attempts
+	}
+	attempts, _ := xerrors.ErrorFields(err)[attemptsField].(int)
+	return attempts
+}
+
+// withAttempts records the number of attempts on err without adding a layer
+// to it. An *xerrors.Error is copied with the attempts added to its fields;
+// other errors are wrapped in an error with the same message.
+func withAttempts(err error, attempts int) error {
+	e, ok := err.(*xerrors.Error) //nolint:errorlint // only the outermost error is copied
+	if !ok {
+		return &attemptsError{err: err, attempts: attempts}
+	}
+
+	cp := *e
+	cp.Fields = make(xerrors.Fields, len(e.Fields)+1)
+	for k, v := range e.Fields {
+		cp.Fields[k] = v
+	}
+	cp.Fields[attemptsField] = attempts
+	return &cp
+}
+
+// attemptsError records the number of attempts on an error that is not an
+// *xerrors.Error. It reads and unwraps as the error itself.
+type attemptsError struct {
+	err      error
+	attempts int
+}
+
+func (e *attemptsError) Error() string {
+	return e.err.Error()
+}
+
+func (e *attemptsError) Unwrap() error {
+	return e.err
+}
+
+// backoff is the state of a single Retry call.
+type backoff struct {
+	p        RetryPolicy
+	deadline time.Time
+	next     time.Duration
+	prev     time.Duration
+}
+
+func (p RetryPolicy) newBackoff() *backoff {
+	deadline := time.Time{}
+	if p.maxElapsedTime > 0 {
+		deadline = time.Now().Add(p.maxElapsedTime)
+	}
+
+	return &backoff{
+		p:        p,
+		deadline: deadline,
+		next:     p.delay,
+		prev:     p.delay,
+	}
+}
+
+// fits reports whether waiting for d stays within the time budget.
+func (b *backoff) fits(d time.Duration) bool {
+	return b.deadline.IsZero() || !time.Now().Add(d).After(b.deadline)
+}
+
+// delay returns the delay before the next retry with backoff.
+func (b *backoff) delay() time.Duration {
+	base := b.next
+	b.next = b.capped(float64(b.next) * b.p.backoffFactor)
+
+	var d time.Duration
+	switch b.p.jitter {
+	case FullJitter:
+		d = b.random(0, base)
+	case EqualJitter:
+		d = b.random(base/2, base-base/2)
+	case DecorrelatedJitter:
+		upper := b.capped(float64(b.prev) * b.p.backoffFactor)
+		if upper < b.p.delay {
+			upper = b.p.delay
+		}
+		d = b.random(b.p.delay, upper-b.p.delay)
+	case NoJitter:
+		d = base
+	}
+
+	d = b.capped(float64(d))
+	b.prev = d
+	return d
+}
+
+// random returns lower plus a random duration between zero and n inclusive.
+func (b *backoff) random(lower, n time.Duration) time.Duration {
+	if n <= 0 || n == math.MaxInt64 {
+		return lower + n
+	}
+	return lower + time.Duration(b.p.rand.Int63n(int64(n)+1))
+}
+
+// capped converts d to a duration no longer than the maximum delay.
+func (b *backoff) capped(d float64) time.Duration {
+	if b.p.maxDelay > 0 && d > float64(b.p.maxDelay) {
+		return b.p.maxDelay
+	}
+	if d >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return time.Duration(d)
+}
+
+func (b *backoff) immediatelyRetry(f func() error, retriesLeft int) error {
 	err := f()
 	if err == nil {
 		return nil
 	}
 
-	if retriesLeft == 0 {
+	if retriesLeft == 0 || !xerrors.IsRetryable(err) || !b.fits(0) {
 		return err
 	}
 
-	return immediatelyRetry(f, retriesLeft-1)
+	return b.immediatelyRetry(f, retriesLeft-1)
 }
 
-func retryWithBackoff(f func() error, retriesLeft int, delay time.Duration, backoff float64) error {
+func (b *backoff) retryWithBackoff(f func() error, retriesLeft int) error {
 	err := f()
 	if err == nil {
 		return nil
 	}
 
-	if retriesLeft == 0 {
+	if retriesLeft == 0 || !xerrors.IsRetryable(err) {
+		return err
+	}
+
+	delay := b.delay()
+	if !b.fits(delay) {
 		return err
 	}
 
 	time.Sleep(delay)
-	return retryWithBackoff(f, retriesLeft-1, time.Duration(float64(delay)*backoff), backoff)
+	return b.retryWithBackoff(f, retriesLeft-1)
 }
@@ -0,0 +1,226 @@
+package xretry_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hardiksachan/x/xerrors"
+	"github.com/hardiksachan/x/xlog"
+	"github.com/hardiksachan/x/xretry"
+	"github.com/stretchr/testify/require"
+)
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string { return "i/o timeout" }
+func (timeoutError) Timeout() bool { return true }
+
+func failing(err error, calls *int) func() error {
+	return func() error {
+		*calls++
+		return err
+	}
+}
+
+func TestRetry(t *testing.T) {
+	r := xretry.NewRetrier(xretry.NewRetryPolicy(
+		xretry.WithImmediateRetries(2),
+		xretry.WithRetriesWithBackoff(2, time.Millisecond, 2),
+	))
+
+	t.Run("retryable errors are retried until retries run out", func(t *testing.T) {
+		calls := 0
+		err := r.Retry(failing(errors.New("connection reset"), &calls))
+
+		require.Error(t, err)
+		require.Equal(t, 6, calls)
+		require.Equal(t, 6, xretry.Attempts(err))
+	})
+
+	t.Run("permanent errors are not retried", func(t *testing.T) {
+		calls := 0
+		cause := xerrors.E(xerrors.Op("test"), xerrors.NotFound)
+		err := r.Retry(failing(cause, &calls))
+
+		require.Equal(t, 1, calls)
+		require.Equal(t, 1, xretry.Attempts(err))
+		require.Equal(t, xerrors.NotFound, xerrors.ErrorCode(err))
+		require.ErrorIs(t, err, &xerrors.Error{Code: xerrors.NotFound})
+	})
+
+	t.Run("errors are returned without an extra layer", func(t *testing.T) {
+		calls := 0
+		cause := xerrors.E(xerrors.Op("test"), xerrors.NotFound, xerrors.Fields{"user_id": 42})
+		err := r.Retry(failing(cause, &calls))
+
+		require.Equal(t, cause.Error(), err.Error())
+		require.Equal(t, []xerrors.Op{"test"}, xerrors.Ops(err))
+		require.Equal(t, 42, xerrors.ErrorFields(err)["user_id"])
+		require.Zero(t, xretry.Attempts(cause))
+
+		calls = 0
+		cause = errors.New("connection reset")
+		err = r.Retry(failing(cause, &calls))
+
+		require.Equal(t, "connection reset", err.Error())
+		require.ErrorIs(t, err, cause)
+		require.Equal(t, calls, xretry.Attempts(err))
+	})
+
+	t.Run("errors can be marked permanent", func(t *testing.T) {
+		calls := 0
+		err := r.Retry(failing(xerrors.E(xerrors.Op("test"), xerrors.Permanent, errors.New("bad payload")), &calls))
+
+		require.Error(t, err)
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("errors can be marked temporary", func(t *testing.T) {
+		calls := 0
+		err := r.Retry(failing(xerrors.E(xerrors.Op("test"), xerrors.NotFound, xerrors.Temporary), &calls))
+
+		require.Error(t, err)
+		require.Equal(t, 6, calls)
+	})
+
+	t.Run("wrapped timeouts are retried", func(t *testing.T) {
+		calls := 0
+		err := r.Retry(failing(xerrors.E(xerrors.Op("test"), xerrors.Invalid, timeoutError{}), &calls))
+
+		require.Error(t, err)
+		require.Equal(t, 6, calls)
+	})
+
+	t.Run("success stops retrying", func(t *testing.T) {
+		calls := 0
+		err := r.Retry(func() error {
+			calls++
+			if calls < 3 {
+				return errors.New("connection reset")
+			}
+			return nil
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, 3, calls)
+	})
+}
+
+func TestRetryWithLogger(t *testing.T) {
+	o := xlog.NewObserver()
+	r := xretry.NewRetrier(xretry.NewRetryPolicy(xretry.WithImmediateRetries(2), xretry.WithLogger(o)))
+
+	calls := 0
+	err := r.Retry(failing(errors.New("connection reset"), &calls))
+
+	require.Error(t, err)
+	entries := o.Entries().ByLevel(xlog.DebugLevel)
+	require.Len(t, entries, calls)
+	require.Len(t, entries.WithField("attempt", calls).WithField("error", "connection reset"), 1)
+
+	t.Run("a nil logger discards messages", func(t *testing.T) {
+		r := xretry.NewRetrier(xretry.NewRetryPolicy(xretry.WithImmediateRetries(1), xretry.WithLogger(nil)))
+
+		calls := 0
+		require.NotPanics(t, func() { _ = r.Retry(failing(errors.New("connection reset"), &calls)) })
+		require.Equal(t, 3, calls)
+	})
+}
+
+// fakeRand records the bounds it is called with and returns the lowest or
+// highest number within them.
+type fakeRand struct {
+	highest bool
+	bounds  []time.Duration
+}
+
+func (r *fakeRand) Int63n(n int64) int64 {
+	r.bounds = append(r.bounds, time.Duration(n))
+	if r.highest {
+		return n - 1
+	}
+	return 0
+}
+
+func TestRetryBackoff(t *testing.T) {
+	retryable := errors.New("connection reset")
+
+	t.Run("delays are capped", func(t *testing.T) {
+		r := xretry.NewRetrier(xretry.NewRetryPolicy(
+			xretry.WithRetriesWithBackoff(3, time.Hour, 2),
+			xretry.WithMaxDelay(time.Millisecond),
+		))
+
+		calls := 0
+		err := r.Retry(failing(retryable, &calls))
+
+		require.Error(t, err)
+		require.Equal(t, 5, calls)
+	})
+
+	t.Run("full jitter waits up to the backoff delay", func(t *testing.T) {
+		rnd := &fakeRand{highest: false, bounds: nil}
+		r := xretry.NewRetrier(xretry.NewRetryPolicy(
+			xretry.WithRetriesWithBackoff(3, time.Hour, 2),
+			xretry.WithJitter(xretry.FullJitter),
+			xretry.WithRandSource(rnd),
+		))
+
+		calls := 0
+		err := r.Retry(failing(retryable, &calls))
+
+		require.Error(t, err)
+		require.Equal(t, 5, calls)
+		require.Equal(t, []time.Duration{time.Hour + 1, 2*time.Hour + 1, 4*time.Hour + 1}, rnd.bounds)
+	})
+
+	t.Run("equal jitter waits at least half the backoff delay", func(t *testing.T) {
+		rnd := &fakeRand{highest: false, bounds: nil}
+		r := xretry.NewRetrier(xretry.NewRetryPolicy(
+			xretry.WithRetriesWithBackoff(2, 2*time.Millisecond, 2),
+			xretry.WithJitter(xretry.EqualJitter),
+			xretry.WithRandSource(rnd),
+		))
+
+		calls := 0
+		err := r.Retry(failing(retryable, &calls))
+
+		require.Error(t, err)
+		require.Equal(t, 4, calls)
+		require.Equal(t, []time.Duration{time.Millisecond + 1, 2*time.Millisecond + 1}, rnd.bounds)
+	})
+
+	t.Run("decorrelated jitter grows from the previous delay up to the cap", func(t *testing.T) {
+		rnd := &fakeRand{highest: true, bounds: nil}
+		r := xretry.NewRetrier(xretry.NewRetryPolicy(
+			xretry.WithRetriesWithBackoff(3, time.Millisecond, 3),
+			xretry.WithJitter(xretry.DecorrelatedJitter),
+			xretry.WithMaxDelay(5*time.Millisecond),
+			xretry.WithRandSource(rnd),
+		))
+
+		calls := 0
+		err := r.Retry(failing(retryable, &calls))
+
+		require.Error(t, err)
+		require.Equal(t, 5, calls)
+		// 1ms-3ms, then 1ms-min(9ms, 5ms), then 1ms-5ms.
+		require.Equal(t, []time.Duration{2*time.Millisecond + 1, 4*time.Millisecond + 1, 4*time.Millisecond + 1}, rnd.bounds)
+	})
+
+	t.Run("delays beyond the time budget are not waited for", func(t *testing.T) {
+		r := xretry.NewRetrier(xretry.NewRetryPolicy(
+			xretry.WithImmediateRetries(2),
+			xretry.WithRetriesWithBackoff(3, time.Hour, 2),
+			xretry.WithMaxElapsedTime(time.Second),
+		))
+
+		calls := 0
+		err := r.Retry(failing(retryable, &calls))
+
+		require.Error(t, err)
+		require.Equal(t, 4, calls)
+		require.Equal(t, 4, xretry.Attempts(err))
+	})
+}
@@ -0,0 +1,152 @@
+package xtest
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hardiksachan/x/xerrors"
+)
+
+const xerrorsImportPath = "github.com/hardiksachan/x/xerrors"
+
+// StrictErrors makes xerrors.E panic on bad arguments for the duration of
+// the test.
+func StrictErrors(t testing.TB) {
+	t.Helper()
+
+	xerrors.SetStrict(true)
+	t.Cleanup(func() { xerrors.SetStrict(false) })
+}
+
+// CheckErrorCalls fails the test for every call to xerrors.E under root
+// that BadErrorCalls flags.
+func CheckErrorCalls(t testing.TB, root string) {
+	t.Helper()
+
+	problems, err := BadErrorCalls(root)
+	if err != nil {
+		t.Fatalf("checking xerrors.E calls: %v", err)
+	}
+	for _, problem := range problems {
+		t.Error(problem)
+	}
+}
+
+// BadErrorCalls parses the Go files under root and reports calls to
+// xerrors.E that have no arguments, or pass literals or formatted strings
+// where an xerrors.Message is expected. Test files, testdata and hidden
+// directories are skipped.
+func BadErrorCalls(root string) ([]string, error) {
+	var problems []string
+	fset := token.NewFileSet()
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if path != root && (strings.HasPrefix(name, ".") || name == "testdata" || name == "vendor") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return err
+		}
+		problems = append(problems, badErrorCallsInFile(fset, file)...)
+		return nil
+	})
+
+	return problems, err
+}
+
+func badErrorCallsInFile(fset *token.FileSet, file *ast.File) []string {
+	isE := errorConstructorMatcher(file)
+	if isE == nil {
+		return nil
+	}
+
+	var problems []string
+	report := func(node ast.Node, format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf("%s: %s", fset.Position(node.Pos()), fmt.Sprintf(format, args...)))
+	}
+
+	ast.Inspect(file, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok || !isE(call.Fun) {
+			return true
+		}
+
+		if len(call.Args) == 0 {
+			report(call, "xerrors.E called with no arguments")
+		}
+		for _, arg := range call.Args {
+			switch arg := arg.(type) {
+			case *ast.BasicLit:
+				report(arg, "literal %s passed to xerrors.E, use a typed argument such as xerrors.Message", arg.Value)
+			case *ast.CallExpr:
+				if isFormatCall(arg.Fun) {
+					report(arg, "formatted string passed to xerrors.E, wrap it in xerrors.Message")
+				}
+			}
+		}
+		return true
+	})
+
+	return problems
+}
+
+// errorConstructorMatcher returns a function reporting whether an
+// expression refers to xerrors.E in the given file, or nil if the file
+// cannot call it.
+func errorConstructorMatcher(file *ast.File) func(ast.Expr) bool {
+	if file.Name.Name == "xerrors" {
+		return func(fun ast.Expr) bool {
+			ident, ok := fun.(*ast.Ident)
+			return ok && ident.Name == "E"
+		}
+	}
+
+	for _, imp := range file.Imports {
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil || path != xerrorsImportPath {
+			continue
+		}
+
+		name := "xerrors"
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		return func(fun ast.Expr) bool {
+			return isSelector(fun, name, "E")
+		}
+	}
+
+	return nil
+}
+
+func isFormatCall(fun ast.Expr) bool {
+	return isSelector(fun, "fmt", "Sprintf") || isSelector(fun, "fmt", "Sprint") || isSelector(fun, "fmt", "Sprintln")
+}
+
+func isSelector(expr ast.Expr, pkg, name string) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != name {
+		return false
+	}
+	ident, ok := sel.X.(*ast.Ident)
+	return ok && ident.Name == pkg
+}
@@ -0,0 +1,39 @@
+package xtest_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hardiksachan/x/xtest"
+	"github.com/stretchr/testify/require"
+)
+
+const badCalls = `package service
+
+import (
+	"fmt"
+
+	xe "github.com/hardiksachan/x/xerrors"
+)
+
+func get(id string) error {
+	op := xe.Op("service.get")
+	_ = xe.E()
+	_ = xe.E(op, "user not found")
+	_ = xe.E(op, fmt.Sprintf("user %s not found", id))
+	return xe.E(op, xe.NotFound, xe.Message("user not found"))
+}
+`
+
+func TestBadErrorCalls(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "service.go"), []byte(badCalls), 0o600))
+
+	problems, err := xtest.BadErrorCalls(dir)
+	require.NoError(t, err)
+	require.Len(t, problems, 3)
+	require.Contains(t, problems[0], "service.go:11")
+	require.Contains(t, problems[1], "service.go:12")
+	require.Contains(t, problems[2], "service.go:13")
+}